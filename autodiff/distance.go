@@ -0,0 +1,84 @@
+package autodiff
+
+import (
+	"github.com/a-h/ml/distance"
+)
+
+// Function is a distance function which records its calculation on a tape, so that it can be
+// used as a loss function. Examples are autodiff.Euclidean and autodiff.SumOfSquares, which
+// match the equivalent functions in the distance package.
+type Function func(p []Var, q []float64) (d Var, err error)
+
+// Chebyshev calculates the chessboard difference between two vectors.
+func Chebyshev(p []Var, q []float64) (d Var, err error) {
+	if err = validateInputs(p, q); err != nil {
+		return
+	}
+	d = Abs(p[0].Sub(Constant(q[0])))
+	for i, pi := range p[1:] {
+		d = Max(d, Abs(pi.Sub(Constant(q[i+1]))))
+	}
+	return
+}
+
+// Euclidean distance between two vectors.
+func Euclidean(p []Var, q []float64) (d Var, err error) {
+	if d, err = SumOfSquares(p, q); err != nil {
+		return
+	}
+	return Sqrt(d), nil
+}
+
+// Manhattan distance between two vectors.
+func Manhattan(p []Var, q []float64) (d Var, err error) {
+	if err = validateInputs(p, q); err != nil {
+		return
+	}
+	d = Constant(0)
+	for i, pi := range p {
+		d = d.Add(Abs(pi.Sub(Constant(q[i]))))
+	}
+	return
+}
+
+// SumOfSquares calculates the sum of squares between two input vectors.
+func SumOfSquares(p []Var, q []float64) (r Var, err error) {
+	if err = validateInputs(p, q); err != nil {
+		return
+	}
+	r = Constant(0)
+	for i, pi := range p {
+		delta := pi.Sub(Constant(q[i]))
+		r = r.Add(delta.Mul(delta))
+	}
+	return
+}
+
+// MeanSquare calculates the mean square distance between two input vectors.
+func MeanSquare(p []Var, q []float64) (r Var, err error) {
+	if r, err = SumOfSquares(p, q); err != nil {
+		return
+	}
+	return r.Scale(1.0 / float64(len(p))), nil
+}
+
+// RootMeanSquare calculates the root mean square (RMS) distance between two input vectors.
+func RootMeanSquare(p []Var, q []float64) (r Var, err error) {
+	if r, err = MeanSquare(p, q); err != nil {
+		return
+	}
+	return Sqrt(r), nil
+}
+
+func validateInputs(p []Var, q []float64) error {
+	if p == nil || q == nil {
+		return distance.ErrNilVector
+	}
+	if len(p) != len(q) {
+		return distance.ErrMismatchedVectorLengths
+	}
+	if len(p) == 0 {
+		return distance.ErrZeroLengthVector
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package autodiff
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/calculus"
+	"github.com/a-h/ml/distance"
+)
+
+func TestDistanceFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        Function
+		expected distance.Function
+	}{
+		{name: "Chebyshev", f: Chebyshev, expected: distance.Chebyshev},
+		{name: "Euclidean", f: Euclidean, expected: distance.Euclidean},
+		{name: "Manhattan", f: Manhattan, expected: distance.Manhattan},
+		{name: "SumOfSquares", f: SumOfSquares, expected: distance.SumOfSquares},
+		{name: "MeanSquare", f: MeanSquare, expected: distance.MeanSquare},
+		{name: "RootMeanSquare", f: RootMeanSquare, expected: distance.RootMeanSquare},
+	}
+	p, q := []float64{1, -2, 3.5}, []float64{0.5, 1, 2}
+
+	for _, test := range tests {
+		tape := NewTape()
+		pv := tape.Variables(p)
+		actual, err := test.f(pv, q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		expected, _ := test.expected(p, q)
+		if math.Abs(actual.Value-expected) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", test.name, expected, actual.Value)
+		}
+		g := tape.Gradient(actual, pv)
+		eg := calculus.Gradient(p, func(v []float64) float64 {
+			d, _ := test.expected(v, q)
+			return d
+		})
+		for i := range eg {
+			if math.Abs(g[i]-eg[i]) > 1e-6 {
+				t.Errorf("%s: expected gradient %v, got %v", test.name, eg, g)
+				break
+			}
+		}
+	}
+}
+
+func TestDistanceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        []Var
+		q        []float64
+		expected error
+	}{
+		{
+			name:     "Zero length vectors",
+			p:        []Var{},
+			q:        []float64{},
+			expected: distance.ErrZeroLengthVector,
+		},
+		{
+			name:     "Mismatched lengths",
+			p:        Constants([]float64{-1}),
+			q:        []float64{3, 3},
+			expected: distance.ErrMismatchedVectorLengths,
+		},
+		{
+			name:     "Nil input",
+			p:        nil,
+			q:        []float64{3, 3},
+			expected: distance.ErrNilVector,
+		},
+	}
+
+	for _, test := range tests {
+		for _, f := range []Function{Chebyshev, Euclidean, Manhattan, SumOfSquares, MeanSquare, RootMeanSquare} {
+			if _, err := f(test.p, test.q); err != test.expected {
+				t.Errorf("%s: expected error %v, got %v", test.name, test.expected, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package autodiff
+
+import (
+	"fmt"
+
+	"github.com/a-h/ml/training"
+)
+
+// Recordable is implemented by trainees which can record their calculation on a tape, using
+// memory in place of the values returned by GetMemory. Examples include rbf.Network and rbf.Node.
+type Recordable interface {
+	GetMemory() []float64
+	Record(t *Tape, memory []Var, input []float64) (output []Var, err error)
+}
+
+// Gradient returns the mean loss of the trainee over the data, and the gradient of the mean loss
+// with respect to each value of the trainee's memory, in the same order as GetMemory.
+func Gradient(r Recordable, d []training.Data, loss Function) (e float64, g []float64, err error) {
+	memory := r.GetMemory()
+	g = make([]float64, len(memory))
+	t := NewTape()
+	for i, td := range d {
+		t.Reset()
+		m := t.Variables(memory)
+		var actual []Var
+		actual, err = r.Record(t, m, td.Input)
+		if err != nil {
+			err = fmt.Errorf("autodiff: error calculating data %d: %v", i, err)
+			return
+		}
+		var l Var
+		l, err = loss(actual, td.Expected)
+		if err != nil {
+			err = fmt.Errorf("autodiff: error calculating loss of data %d: %v", i, err)
+			return
+		}
+		e += l.Value
+		for j, gj := range t.Gradient(l, m) {
+			g[j] += gj
+		}
+	}
+	if len(d) == 0 {
+		return
+	}
+	n := float64(len(d))
+	e /= n
+	for j := range g {
+		g[j] /= n
+	}
+	return
+}
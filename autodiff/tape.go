@@ -0,0 +1,242 @@
+package autodiff
+
+import "math"
+
+// NewTape creates a tape to record calculations on.
+func NewTape() *Tape {
+	return &Tape{}
+}
+
+// Tape records the operations of a calculation, so that the gradient of its result with respect
+// to every variable can be calculated in a single backward pass.
+type Tape struct {
+	nodes []node
+}
+
+// node records the position of the inputs to an operation, and the partial derivative of the
+// operation with respect to each input.
+type node struct {
+	inputs   [2]int
+	partials [2]float64
+}
+
+// Var is a value calculated on a tape. A Var without a tape is a constant.
+type Var struct {
+	tape  *Tape
+	index int
+	// Value of the variable.
+	Value float64
+}
+
+// Variable records a new input variable on the tape.
+func (t *Tape) Variable(x float64) Var {
+	return t.record(x, -1, 0, -1, 0)
+}
+
+// Variables records a new input variable on the tape for each value of x.
+func (t *Tape) Variables(x []float64) []Var {
+	op := make([]Var, len(x))
+	for i, xi := range x {
+		op[i] = t.Variable(xi)
+	}
+	return op
+}
+
+// Len returns the number of operations recorded on the tape.
+func (t *Tape) Len() int {
+	return len(t.nodes)
+}
+
+// Reset clears the tape so it can be reused. Variables recorded before the reset must not be used.
+func (t *Tape) Reset() {
+	t.nodes = t.nodes[:0]
+}
+
+// Gradient returns the partial derivative of the output with respect to each of the variables.
+func (t *Tape) Gradient(of Var, wrt []Var) []float64 {
+	adjoints := t.adjoints(of)
+	op := make([]float64, len(wrt))
+	for i, v := range wrt {
+		if v.tape == t && v.index < len(adjoints) {
+			op[i] = adjoints[v.index]
+		}
+	}
+	return op
+}
+
+// adjoints runs the backward pass, returning the partial derivative of the output with respect
+// to every node on the tape.
+func (t *Tape) adjoints(of Var) []float64 {
+	adjoints := make([]float64, len(t.nodes))
+	if of.tape != t {
+		return adjoints
+	}
+	adjoints[of.index] = 1
+	for i := of.index; i >= 0; i-- {
+		a := adjoints[i]
+		if a == 0 {
+			continue
+		}
+		n := t.nodes[i]
+		for j, in := range n.inputs {
+			if in >= 0 {
+				adjoints[in] += a * n.partials[j]
+			}
+		}
+	}
+	return adjoints
+}
+
+func (t *Tape) record(value float64, a int, da float64, b int, db float64) Var {
+	t.nodes = append(t.nodes, node{
+		inputs:   [2]int{a, b},
+		partials: [2]float64{da, db},
+	})
+	return Var{tape: t, index: len(t.nodes) - 1, Value: value}
+}
+
+// Constant returns a value which is not recorded on a tape, and so has no gradient.
+func Constant(x float64) Var {
+	return Var{index: -1, Value: x}
+}
+
+// Constants returns a constant for each value of x.
+func Constants(x []float64) []Var {
+	op := make([]Var, len(x))
+	for i, xi := range x {
+		op[i] = Constant(xi)
+	}
+	return op
+}
+
+// unary records an operation with a single input.
+func unary(a Var, value, da float64) Var {
+	if a.tape == nil {
+		return Constant(value)
+	}
+	return a.tape.record(value, a.index, da, -1, 0)
+}
+
+// binary records an operation with two inputs, either of which may be a constant.
+func binary(a, b Var, value, da, db float64) Var {
+	t := a.tape
+	if t == nil {
+		t = b.tape
+	}
+	if t == nil {
+		return Constant(value)
+	}
+	ai, bi := a.index, b.index
+	if a.tape == nil {
+		ai, da = -1, 0
+	}
+	if b.tape == nil {
+		bi, db = -1, 0
+	}
+	return t.record(value, ai, da, bi, db)
+}
+
+// Apply records a function of a, given the value of the function and its derivative at a, so
+// that functions which aren't provided by this package can be used.
+func Apply(a Var, value, derivative float64) Var {
+	return unary(a, value, derivative)
+}
+
+// Add returns a + b.
+func (a Var) Add(b Var) Var {
+	return binary(a, b, a.Value+b.Value, 1, 1)
+}
+
+// Sub returns a - b.
+func (a Var) Sub(b Var) Var {
+	return binary(a, b, a.Value-b.Value, 1, -1)
+}
+
+// Mul returns a * b.
+func (a Var) Mul(b Var) Var {
+	return binary(a, b, a.Value*b.Value, b.Value, a.Value)
+}
+
+// Div returns a / b.
+func (a Var) Div(b Var) Var {
+	return binary(a, b, a.Value/b.Value, 1/b.Value, -a.Value/(b.Value*b.Value))
+}
+
+// Neg returns -a.
+func (a Var) Neg() Var {
+	return unary(a, -a.Value, -1)
+}
+
+// Scale returns a * s.
+func (a Var) Scale(s float64) Var {
+	return unary(a, a.Value*s, s)
+}
+
+// Sum returns the sum of the values.
+func Sum(values []Var) Var {
+	op := Constant(0)
+	for _, v := range values {
+		op = op.Add(v)
+	}
+	return op
+}
+
+// Exp returns e**a.
+func Exp(a Var) Var {
+	v := math.Exp(a.Value)
+	return unary(a, v, v)
+}
+
+// Log returns the natural logarithm of a.
+func Log(a Var) Var {
+	return unary(a, math.Log(a.Value), 1/a.Value)
+}
+
+// Sqrt returns the square root of a. The derivative at zero is undefined, so zero is used.
+func Sqrt(a Var) Var {
+	v := math.Sqrt(a.Value)
+	if v == 0 {
+		return unary(a, v, 0)
+	}
+	return unary(a, v, 1/(2*v))
+}
+
+// Pow returns a**p.
+func Pow(a Var, p float64) Var {
+	return unary(a, math.Pow(a.Value, p), p*math.Pow(a.Value, p-1))
+}
+
+// Sin returns the sine of a.
+func Sin(a Var) Var {
+	return unary(a, math.Sin(a.Value), math.Cos(a.Value))
+}
+
+// Cos returns the cosine of a.
+func Cos(a Var) Var {
+	return unary(a, math.Cos(a.Value), -math.Sin(a.Value))
+}
+
+// Tanh returns the hyperbolic tangent of a.
+func Tanh(a Var) Var {
+	v := math.Tanh(a.Value)
+	return unary(a, v, 1-v*v)
+}
+
+// Abs returns the absolute value of a. The derivative at zero is undefined, so zero is used.
+func Abs(a Var) Var {
+	switch {
+	case a.Value > 0:
+		return unary(a, a.Value, 1)
+	case a.Value < 0:
+		return unary(a, -a.Value, -1)
+	}
+	return unary(a, 0, 0)
+}
+
+// Max returns the larger of a and b. The gradient flows to the larger value only.
+func Max(a, b Var) Var {
+	if a.Value >= b.Value {
+		return binary(a, b, a.Value, 1, 0)
+	}
+	return binary(a, b, b.Value, 0, 1)
+}
@@ -0,0 +1,93 @@
+package autodiff
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/calculus"
+)
+
+func TestOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(x []Var) Var
+		x    []float64
+	}{
+		{
+			name: "add, subtract and multiply",
+			f:    func(x []Var) Var { return x[0].Mul(x[1]).Add(x[0]).Sub(x[1].Scale(3)) },
+			x:    []float64{2, 3},
+		},
+		{
+			name: "divide and negate",
+			f:    func(x []Var) Var { return x[0].Div(x[1]).Neg() },
+			x:    []float64{2, 3},
+		},
+		{
+			name: "exp and log",
+			f:    func(x []Var) Var { return Exp(x[0]).Mul(Log(x[1])) },
+			x:    []float64{0.5, 3},
+		},
+		{
+			name: "trigonometry",
+			f:    func(x []Var) Var { return Sin(x[0]).Add(Cos(x[1])).Add(Tanh(x[0].Mul(x[1]))) },
+			x:    []float64{0.5, 0.25},
+		},
+		{
+			name: "powers",
+			f:    func(x []Var) Var { return Pow(x[0], 3).Add(Sqrt(x[1])) },
+			x:    []float64{2, 4},
+		},
+		{
+			name: "abs and max",
+			f:    func(x []Var) Var { return Max(Abs(x[0]), x[1]) },
+			x:    []float64{-2, 1},
+		},
+		{
+			name: "reuse of a variable",
+			f:    func(x []Var) Var { return Sum([]Var{x[0], x[0], x[0].Mul(x[0])}) },
+			x:    []float64{3},
+		},
+		{
+			name: "constants",
+			f:    func(x []Var) Var { return Constant(2).Mul(x[0]).Add(Constant(1).Div(x[0])) },
+			x:    []float64{3},
+		},
+	}
+
+	for _, test := range tests {
+		tape := NewTape()
+		x := tape.Variables(test.x)
+		y := test.f(x)
+		actual := tape.Gradient(y, x)
+		expected := calculus.Gradient(test.x, func(v []float64) float64 {
+			return test.f(Constants(v)).Value
+		})
+		for i := range expected {
+			if math.Abs(actual[i]-expected[i]) > 1e-6 {
+				t.Errorf("%s: expected gradient %v, got %v", test.name, expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestConstantsAreNotRecorded(t *testing.T) {
+	tape := NewTape()
+	x := tape.Variable(2)
+	c := Constant(3).Mul(Constant(4))
+	if c.Value != 12 {
+		t.Errorf("expected 12, got %v", c.Value)
+	}
+	y := x.Mul(c)
+	if tape.Len() != 2 {
+		t.Errorf("expected 2 operations on the tape, got %d", tape.Len())
+	}
+	if g := tape.Gradient(y, []Var{x, c}); g[0] != 12 || g[1] != 0 {
+		t.Errorf("expected gradient [12 0], got %v", g)
+	}
+	tape.Reset()
+	if tape.Len() != 0 {
+		t.Errorf("expected an empty tape after reset, got %d operations", tape.Len())
+	}
+}
@@ -26,22 +26,17 @@ type Data []Datum
 
 // Probability of the data being in a category.
 func (d Data) Probability(is Category, given ...Category) float64 {
-	filtered := filter(d, given...)
-	if len(filtered) == 0 {
-		return 0
-	}
-	matchesInFilteredData := filter(filtered, is)
-	return float64(len(matchesInFilteredData)) / float64(len(filtered))
-}
-
-func filter(d Data, categories ...Category) (filtered Data) {
+	var matched, total int
 	for _, dm := range d {
-		if !matches(dm, categories...) {
+		if !matches(dm, given...) {
 			continue
 		}
-		filtered = append(filtered, dm)
+		total++
+		if matches(dm, is) {
+			matched++
+		}
 	}
-	return
+	return ratio(matched, total)
 }
 
 func matches(dm Datum, categories ...Category) bool {
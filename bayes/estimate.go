@@ -0,0 +1,230 @@
+package bayes
+
+import (
+	"math"
+)
+
+// Estimate of a probability, with the Lower and Upper bounds of its interval.
+type Estimate struct {
+	Probability  float64
+	Lower, Upper float64
+}
+
+// An Estimator estimates a probability from the number of matching datums out of the total.
+// Examples are bayes.MaximumLikelihood, bayes.Additive and bayes.Beta.
+type Estimator func(matched, total int) Estimate
+
+// Estimate the probability of the data being in a category using the estimator.
+func (d Data) Estimate(e Estimator, is Category, given ...Category) Estimate {
+	return estimate(d, e, is, given...)
+}
+
+// Estimate the probability of the data being in a category using the estimator.
+func (idx *Index) Estimate(e Estimator, is Category, given ...Category) Estimate {
+	return estimate(idx, e, is, given...)
+}
+
+func estimate(c counter, e Estimator, is Category, given ...Category) Estimate {
+	return e(c.count(append([]Category{is}, given...)...), c.count(given...))
+}
+
+// MaximumLikelihood estimates the probability as the raw frequency, with a Wilson score interval
+// at the confidence level, e.g. 0.95. As with Data.Probability, if there are no datums, the
+// probability is 0.
+func MaximumLikelihood(confidence float64) Estimator {
+	return func(matched, total int) Estimate {
+		lower, upper := Wilson(matched, total, confidence)
+		return Estimate{
+			Probability: ratio(matched, total),
+			Lower:       lower,
+			Upper:       upper,
+		}
+	}
+}
+
+// Additive (Laplace when alpha is 1) smoothing of the probability, where k is the number of
+// categories the datum could be in, e.g. 2 for a true / false category. The interval is the
+// Wilson score interval at the confidence level, e.g. 0.95, of the smoothed counts, so that it
+// always contains the smoothed probability.
+func Additive(alpha float64, k int, confidence float64) Estimator {
+	return func(matched, total int) Estimate {
+		m, n := float64(matched)+alpha, float64(total)+alpha*float64(k)
+		lower, upper := wilson(m, n, confidence)
+		return Estimate{
+			Probability: smooth(m, n, 1.0/float64(k)),
+			Lower:       lower,
+			Upper:       upper,
+		}
+	}
+}
+
+// MEstimate smooths the probability towards the prior probability, using m virtual datums.
+// The interval is the Wilson score interval at the confidence level, e.g. 0.95, of the counts
+// including the virtual datums, so that it always contains the smoothed probability.
+func MEstimate(m, prior, confidence float64) Estimator {
+	return func(matched, total int) Estimate {
+		sm, sn := float64(matched)+m*prior, float64(total)+m
+		lower, upper := wilson(sm, sn, confidence)
+		return Estimate{
+			Probability: smooth(sm, sn, prior),
+			Lower:       lower,
+			Upper:       upper,
+		}
+	}
+}
+
+// Beta estimates the probability as the mean of the posterior Beta(a + matched, b + unmatched)
+// distribution, given a Beta(a, b) prior. The interval is the equal-tailed credible interval at
+// the confidence level, e.g. 0.95.
+//
+// If either parameter of the posterior isn't positive, e.g. with a Beta(0, 0) prior and no
+// datums, the posterior is improper, and the maximum likelihood estimate is returned instead.
+func Beta(a, b, confidence float64) Estimator {
+	return func(matched, total int) Estimate {
+		pa, pb := a+float64(matched), b+float64(total-matched)
+		if pa <= 0 || pb <= 0 {
+			return MaximumLikelihood(confidence)(matched, total)
+		}
+		tail := (1 - confidence) / 2
+		return Estimate{
+			Probability: pa / (pa + pb),
+			Lower:       BetaQuantile(tail, pa, pb),
+			Upper:       BetaQuantile(1-tail, pa, pb),
+		}
+	}
+}
+
+// Dirichlet estimates the probability of the category with index i, given a Dirichlet prior
+// with the concentration parameters alpha over all of the categories the datum could be in.
+// The marginal of a Dirichlet distribution is a Beta distribution, so this is equivalent to a
+// Beta(alpha[i], sum(alpha) - alpha[i]) prior.
+func Dirichlet(alpha []float64, i int, confidence float64) Estimator {
+	var sum float64
+	for _, a := range alpha {
+		sum += a
+	}
+	return Beta(alpha[i], sum-alpha[i], confidence)
+}
+
+// Wilson returns the Wilson score interval of the binomial proportion at the confidence level,
+// e.g. 0.95. If there are no datums, the interval is 0 to 1.
+func Wilson(matched, total int, confidence float64) (lower, upper float64) {
+	return wilson(float64(matched), float64(total), confidence)
+}
+
+// wilson is Wilson, for counts which include fractional virtual datums.
+func wilson(matched, n, confidence float64) (lower, upper float64) {
+	if n <= 0 {
+		return 0, 1
+	}
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	p := matched / n
+	denominator := 1 + z*z/n
+	centre := (p + z*z/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	lower, upper = math.Max(0, centre-margin), math.Min(1, centre+margin)
+	// Avoid rounding errors at the ends of the interval.
+	if matched <= 0 {
+		lower = 0
+	}
+	if matched >= n {
+		upper = 1
+	}
+	return lower, upper
+}
+
+// BetaQuantile returns the value x at which the cumulative distribution function of the
+// Beta(a, b) distribution equals p.
+func BetaQuantile(p, a, b float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return 1
+	}
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if regularizedIncompleteBeta(mid, a, b) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+func smooth(numerator, denominator, fallback float64) float64 {
+	if denominator == 0 {
+		return fallback
+	}
+	return numerator / denominator
+}
+
+// regularizedIncompleteBeta calculates I_x(a, b), the cumulative distribution function of the
+// Beta(a, b) distribution, using a continued fraction.
+// See Numerical Recipes in C, section 6.4.
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges rapidly for x < (a + 1) / (a + b + 2), otherwise use
+	// the symmetry relation I_x(a, b) = 1 - I_(1-x)(b, a).
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		iterations = 200
+		epsilon    = 1e-15
+		tiny       = 1e-300
+	)
+	qab, qap, qam := a+b, a+1, a-1
+	c, d := 1.0, 1-qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= iterations; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
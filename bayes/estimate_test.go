@@ -0,0 +1,159 @@
+package bayes
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimators(t *testing.T) {
+	tests := []struct {
+		name           string
+		estimator      Estimator
+		matched, total int
+		expected       Estimate
+	}{
+		{
+			name:      "maximum likelihood",
+			estimator: MaximumLikelihood(0.95),
+			matched:   8,
+			total:     10,
+			expected:  Estimate{Probability: 0.8, Lower: 0.4902, Upper: 0.9433},
+		},
+		{
+			name:      "maximum likelihood with no data",
+			estimator: MaximumLikelihood(0.95),
+			expected:  Estimate{Probability: 0, Lower: 0, Upper: 1},
+		},
+		{
+			name:      "laplace",
+			estimator: Additive(1, 2, 0.95),
+			matched:   8,
+			total:     10,
+			expected:  Estimate{Probability: 0.75, Lower: 0.4677, Upper: 0.9111},
+		},
+		{
+			name:      "laplace with no data",
+			estimator: Additive(1, 4, 0.95),
+			expected:  Estimate{Probability: 0.25, Lower: 0.0456, Upper: 0.6994},
+		},
+		{
+			name:      "m-estimate",
+			estimator: MEstimate(10, 0.5, 0.95),
+			matched:   8,
+			total:     10,
+			expected:  Estimate{Probability: 0.65, Lower: 0.4329, Upper: 0.8188},
+		},
+		{
+			name:      "uniform beta prior",
+			estimator: Beta(1, 1, 0.95),
+			matched:   8,
+			total:     10,
+			expected:  Estimate{Probability: 0.75, Lower: 0.4822, Upper: 0.9398},
+		},
+		{
+			name:      "uniform beta prior with no data",
+			estimator: Beta(1, 1, 0.9),
+			expected:  Estimate{Probability: 0.5, Lower: 0.05, Upper: 0.95},
+		},
+		{
+			name:      "improper beta prior with no data",
+			estimator: Beta(0, 0, 0.95),
+			expected:  Estimate{Probability: 0, Lower: 0, Upper: 1},
+		},
+		{
+			name:      "improper beta prior with all datums matched",
+			estimator: Beta(0, 0, 0.95),
+			matched:   5,
+			total:     5,
+			expected:  Estimate{Probability: 1, Lower: 0.5655, Upper: 1},
+		},
+		{
+			name:      "dirichlet",
+			estimator: Dirichlet([]float64{1, 0.5, 0.5}, 0, 0.95),
+			matched:   8,
+			total:     10,
+			expected:  Estimate{Probability: 0.75, Lower: 0.4822, Upper: 0.9398},
+		},
+	}
+
+	for _, test := range tests {
+		actual := test.estimator(test.matched, test.total)
+		if !estimateEq(actual, test.expected, 1e-4) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestEstimatesAreWithinTheirIntervals(t *testing.T) {
+	estimators := []struct {
+		name      string
+		estimator Estimator
+	}{
+		{name: "maximum likelihood", estimator: MaximumLikelihood(0.95)},
+		{name: "laplace", estimator: Additive(1, 2, 0.95)},
+		{name: "strong additive", estimator: Additive(50, 2, 0.95)},
+		{name: "m-estimate", estimator: MEstimate(10, 0.5, 0.95)},
+		{name: "strong m-estimate", estimator: MEstimate(1000, 0.1, 0.95)},
+		{name: "uniform beta prior", estimator: Beta(1, 1, 0.95)},
+		{name: "improper beta prior", estimator: Beta(0, 0, 0.95)},
+		{name: "jeffreys beta prior", estimator: Beta(0.5, 0.5, 0.5)},
+		{name: "dirichlet", estimator: Dirichlet([]float64{1, 0.5, 0.5}, 0, 0.95)},
+	}
+
+	for _, test := range estimators {
+		for total := 0; total <= 20; total++ {
+			for matched := 0; matched <= total; matched++ {
+				e := test.estimator(matched, total)
+				if math.IsNaN(e.Probability) || e.Lower > e.Probability || e.Probability > e.Upper {
+					t.Errorf("%s: %d of %d: expected %v to be between %v and %v", test.name, matched, total, e.Probability, e.Lower, e.Upper)
+				}
+			}
+		}
+	}
+}
+
+func TestDataEstimate(t *testing.T) {
+	d := people()
+	e := Additive(1, 2, 0.95)
+	expected := Estimate{Probability: 0.5}
+	// 2 of 4 datums match, and Laplace smoothing adds 1 of 2 virtual datums.
+	expected.Lower, expected.Upper = Wilson(3, 6, 0.95)
+	if actual := d.Estimate(e, categoryBlondeHair, categoryBlueEyes); !estimateEq(actual, expected, 1e-12) {
+		t.Errorf("data: expected %+v, got %+v", expected, actual)
+	}
+	if actual := NewIndex(d).Estimate(e, categoryBlondeHair, categoryBlueEyes); !estimateEq(actual, expected, 1e-12) {
+		t.Errorf("index: expected %+v, got %+v", expected, actual)
+	}
+	// Unlike Probability, a category which isn't in the data doesn't have a zero probability.
+	if actual := d.Estimate(e, Category(100), Category(200)); actual.Probability != 0.5 {
+		t.Errorf("expected a probability of 0.5 for missing categories, got %v", actual.Probability)
+	}
+}
+
+func TestBetaQuantile(t *testing.T) {
+	tests := []struct {
+		p, a, b  float64
+		expected float64
+	}{
+		{p: 0.5, a: 1, b: 1, expected: 0.5},
+		{p: 0.25, a: 1, b: 1, expected: 0.25},
+		{p: 0.5, a: 2, b: 2, expected: 0.5},
+		// For Beta(2, 1), the CDF is x^2.
+		{p: 0.25, a: 2, b: 1, expected: 0.5},
+		{p: 0, a: 3, b: 4, expected: 0},
+		{p: 1, a: 3, b: 4, expected: 1},
+	}
+
+	for _, test := range tests {
+		actual := BetaQuantile(test.p, test.a, test.b)
+		if math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("Beta(%v, %v) quantile %v: expected %v, got %v", test.a, test.b, test.p, test.expected, actual)
+		}
+	}
+}
+
+func estimateEq(a, b Estimate, tolerance float64) bool {
+	return math.Abs(a.Probability-b.Probability) <= tolerance &&
+		math.Abs(a.Lower-b.Lower) <= tolerance &&
+		math.Abs(a.Upper-b.Upper) <= tolerance
+}
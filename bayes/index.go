@@ -0,0 +1,159 @@
+package bayes
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// NewIndex creates an Index of the data. The index records which datums are members of each
+// category, so that queries don't need to scan all of the data.
+func NewIndex(d Data) *Index {
+	idx := &Index{
+		categories: make(map[Category]bitset),
+	}
+	for _, dm := range d {
+		idx.Add(dm)
+	}
+	return idx
+}
+
+// Index holds a bitset of category membership for each category in the data, so that counting
+// the datums which are members of a combination of categories only needs to intersect the
+// bitsets.
+//
+// Queries don't modify the index, so they can be made from multiple goroutines at once, but Add
+// and Remove must not be called concurrently with each other or with queries.
+type Index struct {
+	// members holds the categories of each datum, or nil if the datum has been removed.
+	members    [][]Category
+	free       []int
+	size       int
+	categories map[Category]bitset
+}
+
+// Add a datum to the index, returning an id which can be used to remove it.
+func (idx *Index) Add(dm Datum) (id int) {
+	members := make([]Category, 0, len(dm.Categories))
+	for c := range dm.Categories {
+		members = append(members, c)
+	}
+	if len(idx.free) > 0 {
+		id = idx.free[len(idx.free)-1]
+		idx.free = idx.free[:len(idx.free)-1]
+		idx.members[id] = members
+	} else {
+		id = len(idx.members)
+		idx.members = append(idx.members, members)
+	}
+	for _, c := range members {
+		idx.categories[c] = idx.categories[c].set(id)
+	}
+	idx.size++
+	return id
+}
+
+// Remove the datum with the id returned by Add from the index.
+func (idx *Index) Remove(id int) error {
+	if id < 0 || id >= len(idx.members) || idx.members[id] == nil {
+		return fmt.Errorf("bayes: datum %d is not in the index", id)
+	}
+	for _, c := range idx.members[id] {
+		idx.categories[c].clear(id)
+	}
+	idx.members[id] = nil
+	idx.free = append(idx.free, id)
+	idx.size--
+	return nil
+}
+
+// Len returns the number of datums in the index.
+func (idx *Index) Len() int {
+	return idx.size
+}
+
+// Count returns the number of datums which are members of all of the categories.
+func (idx *Index) Count(categories ...Category) int {
+	return idx.count(categories...)
+}
+
+// Probability of the data being in a category.
+func (idx *Index) Probability(is Category, given ...Category) float64 {
+	return conditional(idx, is, given...)
+}
+
+// Joint returns the probability of the data being in all of the categories at once.
+func (idx *Index) Joint(categories ...Category) float64 {
+	return joint(idx, categories...)
+}
+
+// Marginal returns the probability of the data being in a category, regardless of any other
+// categories the data is in.
+func (idx *Index) Marginal(is Category) float64 {
+	return joint(idx, is)
+}
+
+// Posterior calculates the probability of the data being in a category given other categories
+// using Bayes' theorem.
+func (idx *Index) Posterior(is Category, given ...Category) float64 {
+	return posterior(idx, is, given...)
+}
+
+// ConditionallyIndependent returns true when categories a and b are independent once the given
+// categories are known, i.e. P(a, b | given) = P(a | given) P(b | given) within the tolerance.
+func (idx *Index) ConditionallyIndependent(a, b Category, tolerance float64, given ...Category) bool {
+	return conditionallyIndependent(idx, a, b, tolerance, given...)
+}
+
+// Confidence of the association rule antecedent => consequent.
+func (idx *Index) Confidence(consequent Category, antecedent ...Category) float64 {
+	return conditional(idx, consequent, antecedent...)
+}
+
+// Lift of the association rule antecedent => consequent.
+func (idx *Index) Lift(consequent Category, antecedent ...Category) float64 {
+	return lift(idx, consequent, antecedent...)
+}
+
+func (idx *Index) count(categories ...Category) int {
+	if len(categories) == 0 {
+		return idx.size
+	}
+	return idx.intersect(categories)
+}
+
+func (idx *Index) intersect(categories []Category) (n int) {
+	sets := make([]bitset, len(categories))
+	words := -1
+	for i, c := range categories {
+		sets[i] = idx.categories[c]
+		if words < 0 || len(sets[i]) < words {
+			words = len(sets[i])
+		}
+	}
+	for w := 0; w < words; w++ {
+		word := sets[0][w]
+		for _, bs := range sets[1:] {
+			word &= bs[w]
+		}
+		n += bits.OnesCount64(word)
+	}
+	return
+}
+
+// bitset records membership of a set, one bit per datum.
+type bitset []uint64
+
+// set the bit i, growing the bitset if required.
+func (bs bitset) set(i int) bitset {
+	for len(bs) <= i/64 {
+		bs = append(bs, 0)
+	}
+	bs[i/64] |= 1 << uint(i%64)
+	return bs
+}
+
+func (bs bitset) clear(i int) {
+	if i/64 < len(bs) {
+		bs[i/64] &^= 1 << uint(i%64)
+	}
+}
@@ -0,0 +1,150 @@
+package bayes
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIndexMatchesData(t *testing.T) {
+	d := make(Data, 200)
+	for i := range d {
+		var categories []Category
+		for c := Category(1); c <= 5; c++ {
+			if rand.Intn(2) == 0 {
+				categories = append(categories, c)
+			}
+		}
+		d[i] = NewDatum(i, categories...)
+	}
+	idx := NewIndex(d)
+
+	queries := [][]Category{
+		{},
+		{1},
+		{1, 2},
+		{2, 3, 4},
+		{5, 5},
+		{6},
+	}
+	for _, q := range queries {
+		if expected, actual := d.Count(q...), idx.Count(q...); expected != actual {
+			t.Errorf("count of %v: expected %d, got %d", q, expected, actual)
+		}
+		if expected, actual := d.Joint(q...), idx.Joint(q...); expected != actual {
+			t.Errorf("joint of %v: expected %v, got %v", q, expected, actual)
+		}
+		for is := Category(1); is <= 6; is++ {
+			if expected, actual := d.Probability(is, q...), idx.Probability(is, q...); expected != actual {
+				t.Errorf("probability of %v given %v: expected %v, got %v", is, q, expected, actual)
+			}
+			if expected, actual := d.Posterior(is, q...), idx.Posterior(is, q...); expected != actual {
+				t.Errorf("posterior of %v given %v: expected %v, got %v", is, q, expected, actual)
+			}
+			if expected, actual := d.Lift(is, q...), idx.Lift(is, q...); expected != actual {
+				t.Errorf("lift of %v given %v: expected %v, got %v", is, q, expected, actual)
+			}
+		}
+	}
+}
+
+func TestIndexOfEmptyData(t *testing.T) {
+	idx := NewIndex(nil)
+	if actual := idx.Probability(Category(1)); actual != 0 {
+		t.Errorf("expected 0, got %v", actual)
+	}
+	if actual := idx.Count(); actual != 0 {
+		t.Errorf("expected 0, got %v", actual)
+	}
+}
+
+func TestIndexAddAndRemove(t *testing.T) {
+	d := people()
+	idx := NewIndex(d)
+
+	if actual := idx.Probability(categoryBlondeHair, categoryBlueEyes); actual != 0.5 {
+		t.Fatalf("expected 0.5, got %v", actual)
+	}
+
+	id := idx.Add(NewDatum("Jill", categoryBlueEyes, categoryBlondeHair))
+	d = append(d, NewDatum("Jill", categoryBlueEyes, categoryBlondeHair))
+	if expected, actual := d.Probability(categoryBlondeHair, categoryBlueEyes), idx.Probability(categoryBlondeHair, categoryBlueEyes); expected != actual {
+		t.Errorf("after add: expected %v, got %v", expected, actual)
+	}
+	if idx.Len() != len(d) {
+		t.Errorf("after add: expected length %d, got %d", len(d), idx.Len())
+	}
+
+	if err := idx.Remove(id); err != nil {
+		t.Fatalf("unexpected error removing datum: %v", err)
+	}
+	if err := idx.Remove(id); err == nil {
+		t.Errorf("expected an error removing a datum twice")
+	}
+	if err := idx.Remove(100); err == nil {
+		t.Errorf("expected an error removing a datum which doesn't exist")
+	}
+	d = d[:len(d)-1]
+	if expected, actual := d.Probability(categoryBlondeHair, categoryBlueEyes), idx.Probability(categoryBlondeHair, categoryBlueEyes); expected != actual {
+		t.Errorf("after remove: expected %v, got %v", expected, actual)
+	}
+
+	// Removed slots are reused.
+	if reused := idx.Add(NewDatum("Jack", categoryBrownEyes)); reused != id {
+		t.Errorf("expected the removed id %d to be reused, got %d", id, reused)
+	}
+	if actual := idx.Count(categoryBrownEyes); actual != 2 {
+		t.Errorf("expected 2 datums with brown eyes, got %d", actual)
+	}
+	if actual := idx.Count(categoryBlueEyes, categoryBlondeHair, categoryBlueEyes); actual != 2 {
+		t.Errorf("expected duplicate categories to be ignored, got %d", actual)
+	}
+}
+
+func TestIndexConcurrentQueries(t *testing.T) {
+	d := people()
+	idx := NewIndex(d)
+	expected := d.Probability(categoryBlondeHair, categoryBlueEyes)
+	results := make(chan float64)
+	for i := 0; i < 8; i++ {
+		go func() {
+			results <- idx.Probability(categoryBlondeHair, categoryBlueEyes)
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		if actual := <-results; actual != expected {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+	}
+}
+
+func BenchmarkDataProbability(b *testing.B) {
+	d := benchmarkData(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		d.Probability(1, 2, 3)
+	}
+}
+
+func BenchmarkIndexProbability(b *testing.B) {
+	idx := NewIndex(benchmarkData(100000))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		idx.Probability(1, 2, 3)
+	}
+}
+
+func benchmarkData(quantity int) Data {
+	d := make(Data, quantity)
+	for i := range d {
+		var categories []Category
+		for c := Category(1); c <= 10; c++ {
+			if rand.Intn(2) == 0 {
+				categories = append(categories, c)
+			}
+		}
+		d[i] = NewDatum(i, categories...)
+	}
+	return d
+}
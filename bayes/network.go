@@ -0,0 +1,497 @@
+package bayes
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/a-h/ml/random"
+)
+
+// Variable is a discrete random variable in a Bayesian network. Each state of the variable
+// is a Category, so that the variable's conditional probability table can be learned from Data.
+type Variable struct {
+	Name string
+	// States of the variable. A datum is in the state when it's a member of the Category.
+	States []Category
+	// Parents are the names of the variables that this variable is conditionally dependent on.
+	Parents []string
+}
+
+// NewNetwork creates a Bayesian network from the variables, checking that the parents of each
+// variable exist, that every state Category is used only once and that the graph is acyclic.
+// Each variable is given a uniform conditional probability table until Learn or
+// SetProbabilities is used.
+func NewNetwork(variables ...Variable) (n *Network, err error) {
+	if len(variables) == 0 {
+		err = errors.New("bayes: unable to create network, since there are no variables")
+		return
+	}
+	n = &Network{
+		Variables: variables,
+		index:     make(map[string]int, len(variables)),
+		states:    make(map[Category]stateOf),
+		parents:   make([][]int, len(variables)),
+		tables:    make([][]float64, len(variables)),
+	}
+	for i, v := range variables {
+		if _, ok := n.index[v.Name]; ok {
+			err = fmt.Errorf("bayes: duplicate variable name %q", v.Name)
+			return
+		}
+		if len(v.States) == 0 {
+			err = fmt.Errorf("bayes: variable %q has no states", v.Name)
+			return
+		}
+		n.index[v.Name] = i
+		for j, c := range v.States {
+			if existing, ok := n.states[c]; ok {
+				err = fmt.Errorf("bayes: category %v of variable %q is already used by variable %q",
+					c, v.Name, variables[existing.variable].Name)
+				return
+			}
+			n.states[c] = stateOf{variable: i, state: j}
+		}
+	}
+	for i, v := range variables {
+		for _, p := range v.Parents {
+			pi, ok := n.index[p]
+			if !ok {
+				err = fmt.Errorf("bayes: parent %q of variable %q not found", p, v.Name)
+				return
+			}
+			n.parents[i] = append(n.parents[i], pi)
+		}
+	}
+	if n.order, err = n.topologicalOrder(); err != nil {
+		return
+	}
+	for i := range variables {
+		t := make([]float64, n.tableSize(i))
+		for j := range t {
+			t[j] = 1.0 / float64(len(variables[i].States))
+		}
+		n.tables[i] = t
+	}
+	return
+}
+
+// Network is a Bayesian network: a directed acyclic graph of discrete variables, each with a
+// conditional probability table giving the probability of its states given its parents.
+type Network struct {
+	Variables []Variable
+	index     map[string]int
+	states    map[Category]stateOf
+	parents   [][]int
+	order     []int
+	// tables holds the conditional probability table of each variable. The table is laid out
+	// with one row per combination of parent states (the last parent changing fastest) and one
+	// column per state of the variable.
+	tables [][]float64
+}
+
+type stateOf struct {
+	variable, state int
+}
+
+// SetProbabilities sets the conditional probability table of the named variable. The table
+// has a row for each combination of parent states, with the last parent's state changing
+// fastest, and a column for each state of the variable.
+func (n *Network) SetProbabilities(name string, table []float64) error {
+	i, ok := n.index[name]
+	if !ok {
+		return fmt.Errorf("bayes: variable %q not found", name)
+	}
+	if len(table) != n.tableSize(i) {
+		return fmt.Errorf("bayes: variable %q requires a table of %d values, but got %d",
+			name, n.tableSize(i), len(table))
+	}
+	n.tables[i] = append([]float64(nil), table...)
+	return nil
+}
+
+// Probabilities returns a copy of the conditional probability table of the named variable.
+func (n *Network) Probabilities(name string) ([]float64, error) {
+	i, ok := n.index[name]
+	if !ok {
+		return nil, fmt.Errorf("bayes: variable %q not found", name)
+	}
+	return append([]float64(nil), n.tables[i]...), nil
+}
+
+// Learn the conditional probability tables from the data. Datums which aren't in a state of a
+// variable or its parents are ignored for that variable. alpha is added to every count, so that
+// combinations which don't appear in the data don't have zero probability, an alpha of 1 is
+// Laplace smoothing.
+func (n *Network) Learn(d Data, alpha float64) error {
+	if alpha < 0 {
+		return fmt.Errorf("bayes: alpha must not be negative, but got %v", alpha)
+	}
+	idx := NewIndex(d)
+	for i, v := range n.Variables {
+		states := len(v.States)
+		t := make([]float64, n.tableSize(i))
+		given := make([]Category, len(n.parents[i]))
+		for row := 0; row < len(t)/states; row++ {
+			n.parentStates(i, row, given)
+			counts := make([]float64, states)
+			var total float64
+			for s, c := range v.States {
+				counts[s] = float64(idx.Count(append([]Category{c}, given...)...)) + alpha
+				total += counts[s]
+			}
+			for s := range counts {
+				if total == 0 {
+					t[row*states+s] = 1.0 / float64(states)
+					continue
+				}
+				t[row*states+s] = counts[s] / total
+			}
+		}
+		n.tables[i] = t
+	}
+	return nil
+}
+
+// Probability of being in a category given the evidence categories, calculated exactly using
+// variable elimination.
+func (n *Network) Probability(is Category, given ...Category) (float64, error) {
+	s, ok := n.states[is]
+	if !ok {
+		return 0, fmt.Errorf("bayes: category %v is not a state of any variable", is)
+	}
+	p, err := n.Query(n.Variables[s.variable].Name, given...)
+	if err != nil {
+		return 0, err
+	}
+	return p[s.state], nil
+}
+
+// Query returns the probability of each state of the named variable given the evidence
+// categories, calculated exactly using variable elimination.
+func (n *Network) Query(name string, given ...Category) ([]float64, error) {
+	q, evidence, err := n.prepare(name, given)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a factor for each variable, removing the evidence.
+	factors := make([]factor, len(n.Variables))
+	for i := range n.Variables {
+		f := n.factor(i)
+		for _, v := range f.variables {
+			if s, ok := evidence[v]; ok {
+				f = f.reduce(v, s)
+			}
+		}
+		factors[i] = f
+	}
+
+	// Sum out each hidden variable in reverse topological order.
+	for oi := len(n.order) - 1; oi >= 0; oi-- {
+		v := n.order[oi]
+		if _, ok := evidence[v]; ok || v == q {
+			continue
+		}
+		var remaining []factor
+		var product factor
+		var found bool
+		for _, f := range factors {
+			if !f.contains(v) {
+				remaining = append(remaining, f)
+				continue
+			}
+			if !found {
+				product, found = f, true
+				continue
+			}
+			product = product.product(f)
+		}
+		if found {
+			remaining = append(remaining, product.sumOut(v))
+		}
+		factors = remaining
+	}
+
+	result := factors[0]
+	for _, f := range factors[1:] {
+		result = result.product(f)
+	}
+	return normalise(result.values)
+}
+
+// Estimate returns the probability of each state of the named variable given the evidence
+// categories, approximated using likelihood-weighted sampling.
+func (n *Network) Estimate(name string, samples int, given ...Category) ([]float64, error) {
+	if samples <= 0 {
+		return nil, fmt.Errorf("bayes: samples must be greater than zero, but got %d", samples)
+	}
+	q, evidence, err := n.prepare(name, given)
+	if err != nil {
+		return nil, err
+	}
+	op := make([]float64, len(n.Variables[q].States))
+	assignment := make([]int, len(n.Variables))
+	for i := 0; i < samples; i++ {
+		weight := 1.0
+		for _, v := range n.order {
+			row := n.row(v, assignment)
+			if s, ok := evidence[v]; ok {
+				assignment[v] = s
+				weight *= row[s]
+				continue
+			}
+			assignment[v] = sample(row)
+		}
+		op[assignment[q]] += weight
+	}
+	return normalise(op)
+}
+
+// prepare looks up the query variable and converts the evidence categories into a map of
+// variable index to state index.
+func (n *Network) prepare(name string, given []Category) (q int, evidence map[int]int, err error) {
+	q, ok := n.index[name]
+	if !ok {
+		err = fmt.Errorf("bayes: variable %q not found", name)
+		return
+	}
+	evidence = make(map[int]int, len(given))
+	for _, c := range given {
+		s, ok := n.states[c]
+		if !ok {
+			err = fmt.Errorf("bayes: evidence category %v is not a state of any variable", c)
+			return
+		}
+		if existing, ok := evidence[s.variable]; ok && existing != s.state {
+			err = fmt.Errorf("bayes: conflicting evidence for variable %q", n.Variables[s.variable].Name)
+			return
+		}
+		if s.variable == q {
+			err = fmt.Errorf("bayes: variable %q cannot be both queried and used as evidence", name)
+			return
+		}
+		evidence[s.variable] = s.state
+	}
+	return
+}
+
+func (n *Network) tableSize(i int) int {
+	size := len(n.Variables[i].States)
+	for _, p := range n.parents[i] {
+		size *= len(n.Variables[p].States)
+	}
+	return size
+}
+
+// parentStates sets the categories of the parent states for the row of the table of variable i.
+func (n *Network) parentStates(i, row int, categories []Category) {
+	for pi := len(n.parents[i]) - 1; pi >= 0; pi-- {
+		p := n.parents[i][pi]
+		states := len(n.Variables[p].States)
+		categories[pi] = n.Variables[p].States[row%states]
+		row /= states
+	}
+}
+
+// row returns the probability of each state of variable i, given the states of its parents in
+// the assignment.
+func (n *Network) row(i int, assignment []int) []float64 {
+	var r int
+	for _, p := range n.parents[i] {
+		r = r*len(n.Variables[p].States) + assignment[p]
+	}
+	states := len(n.Variables[i].States)
+	return n.tables[i][r*states : (r+1)*states]
+}
+
+func (n *Network) factor(i int) factor {
+	variables := append(append([]int(nil), n.parents[i]...), i)
+	cards := make([]int, len(variables))
+	for j, v := range variables {
+		cards[j] = len(n.Variables[v].States)
+	}
+	return factor{
+		variables: variables,
+		cards:     cards,
+		values:    append([]float64(nil), n.tables[i]...),
+	}
+}
+
+func (n *Network) topologicalOrder() (order []int, err error) {
+	children := make([][]int, len(n.Variables))
+	incoming := make([]int, len(n.Variables))
+	for i, ps := range n.parents {
+		incoming[i] = len(ps)
+		for _, p := range ps {
+			children[p] = append(children[p], i)
+		}
+	}
+	var queue []int
+	for i, c := range incoming {
+		if c == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		order = append(order, v)
+		for _, c := range children[v] {
+			incoming[c]--
+			if incoming[c] == 0 {
+				queue = append(queue, c)
+			}
+		}
+	}
+	if len(order) != len(n.Variables) {
+		err = errors.New("bayes: the network contains a cycle")
+	}
+	return
+}
+
+func sample(p []float64) int {
+	r := random.Float64(0, 1)
+	for i, pi := range p {
+		if r < pi {
+			return i
+		}
+		r -= pi
+	}
+	return len(p) - 1
+}
+
+func normalise(values []float64) ([]float64, error) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	if sum == 0 {
+		return nil, errors.New("bayes: the evidence has zero probability")
+	}
+	op := make([]float64, len(values))
+	for i, v := range values {
+		op[i] = v / sum
+	}
+	return op, nil
+}
+
+// factor is a table of values over the states of a set of variables, laid out with the last
+// variable's state changing fastest.
+type factor struct {
+	variables []int
+	cards     []int
+	values    []float64
+}
+
+func newFactor(variables, cards []int) factor {
+	size := 1
+	for _, c := range cards {
+		size *= c
+	}
+	return factor{
+		variables: variables,
+		cards:     cards,
+		values:    make([]float64, size),
+	}
+}
+
+func (f factor) contains(v int) bool {
+	return f.position(v) >= 0
+}
+
+func (f factor) position(v int) int {
+	for i, fv := range f.variables {
+		if fv == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f factor) product(g factor) factor {
+	variables := append([]int(nil), f.variables...)
+	cards := append([]int(nil), f.cards...)
+	for i, v := range g.variables {
+		if !f.contains(v) {
+			variables = append(variables, v)
+			cards = append(cards, g.cards[i])
+		}
+	}
+	r := newFactor(variables, cards)
+	fp, gp := r.positions(f), r.positions(g)
+	each(cards, func(assignment []int, i int) {
+		r.values[i] = f.values[f.index(assignment, fp)] * g.values[g.index(assignment, gp)]
+	})
+	return r
+}
+
+func (f factor) sumOut(v int) factor {
+	r := f.without(v)
+	rp := f.positions(r)
+	each(f.cards, func(assignment []int, i int) {
+		r.values[r.index(assignment, rp)] += f.values[i]
+	})
+	return r
+}
+
+func (f factor) reduce(v, state int) factor {
+	r := f.without(v)
+	// Read the state of v from the end of the assignment.
+	fp := r.positions(f)
+	fp[f.position(v)] = len(r.variables)
+	full := make([]int, len(r.variables)+1)
+	full[len(r.variables)] = state
+	each(r.cards, func(assignment []int, i int) {
+		copy(full, assignment)
+		r.values[i] = f.values[f.index(full, fp)]
+	})
+	return r
+}
+
+func (f factor) without(v int) factor {
+	var variables, cards []int
+	for i, fv := range f.variables {
+		if fv != v {
+			variables = append(variables, fv)
+			cards = append(cards, f.cards[i])
+		}
+	}
+	return newFactor(variables, cards)
+}
+
+// positions returns the position of each of g's variables within f's variables.
+func (f factor) positions(g factor) []int {
+	op := make([]int, len(g.variables))
+	for i, v := range g.variables {
+		op[i] = f.position(v)
+	}
+	return op
+}
+
+// index returns the index into the factor of the assignment, where the state of each of the
+// factor's variables is read from the assignment at the position given.
+func (f factor) index(assignment []int, positions []int) (i int) {
+	for k, c := range f.cards {
+		i = i*c + assignment[positions[k]]
+	}
+	return
+}
+
+// each calls fn with every assignment of states, in the order of the factor's values.
+func each(cards []int, fn func(assignment []int, i int)) {
+	assignment := make([]int, len(cards))
+	size := 1
+	for _, c := range cards {
+		size *= c
+	}
+	for i := 0; i < size; i++ {
+		fn(assignment, i)
+		for k := len(cards) - 1; k >= 0; k-- {
+			assignment[k]++
+			if assignment[k] < cards[k] {
+				break
+			}
+			assignment[k] = 0
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package bayes
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	cloudy Category = iota + 100
+	clear
+	sprinklerOn
+	sprinklerOff
+	raining
+	dry
+	wet
+	notWet
+)
+
+func sprinkler(t *testing.T) *Network {
+	n, err := NewNetwork(
+		Variable{Name: "Cloudy", States: []Category{cloudy, clear}},
+		Variable{Name: "Sprinkler", States: []Category{sprinklerOn, sprinklerOff}, Parents: []string{"Cloudy"}},
+		Variable{Name: "Rain", States: []Category{raining, dry}, Parents: []string{"Cloudy"}},
+		Variable{Name: "WetGrass", States: []Category{wet, notWet}, Parents: []string{"Sprinkler", "Rain"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	tables := map[string][]float64{
+		"Cloudy": {0.5, 0.5},
+		"Sprinkler": {
+			0.1, 0.9, // Cloudy
+			0.5, 0.5, // Clear
+		},
+		"Rain": {
+			0.8, 0.2, // Cloudy
+			0.2, 0.8, // Clear
+		},
+		"WetGrass": {
+			0.99, 0.01, // Sprinkler on, raining
+			0.9, 0.1, // Sprinkler on, dry
+			0.9, 0.1, // Sprinkler off, raining
+			0.0, 1.0, // Sprinkler off, dry
+		},
+	}
+	for name, table := range tables {
+		if err := n.SetProbabilities(name, table); err != nil {
+			t.Fatalf("unexpected error setting probabilities of %s: %v", name, err)
+		}
+	}
+	return n
+}
+
+func TestNetworkInference(t *testing.T) {
+	n := sprinkler(t)
+	tests := []struct {
+		name     string
+		is       Category
+		given    []Category
+		expected float64
+	}{
+		{
+			name:     "prior",
+			is:       cloudy,
+			expected: 0.5,
+		},
+		{
+			name:     "rain given wet grass",
+			is:       raining,
+			given:    []Category{wet},
+			expected: 0.7079,
+		},
+		{
+			name:     "sprinkler given wet grass",
+			is:       sprinklerOn,
+			given:    []Category{wet},
+			expected: 0.4298,
+		},
+		{
+			name:     "sprinkler given wet grass and rain",
+			is:       sprinklerOn,
+			given:    []Category{wet, raining},
+			expected: 0.1945,
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := n.Probability(test.is, test.given...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if math.Abs(actual-test.expected) > 1e-4 {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+		}
+
+		s := n.states[test.is]
+		estimate, err := n.Estimate(n.Variables[s.variable].Name, 50000, test.given...)
+		if err != nil {
+			t.Errorf("%s: unexpected error estimating: %v", test.name, err)
+			continue
+		}
+		if math.Abs(estimate[s.state]-test.expected) > 0.02 {
+			t.Errorf("%s: expected estimate close to %v, got %v", test.name, test.expected, estimate[s.state])
+		}
+	}
+}
+
+func TestNetworkLearn(t *testing.T) {
+	d := Data{
+		NewDatum(1, cloudy, raining),
+		NewDatum(2, cloudy, raining),
+		NewDatum(3, cloudy, dry),
+		NewDatum(4, clear, dry),
+		NewDatum(5, clear, raining),
+		NewDatum(6, clear, dry),
+		NewDatum(7, clear, dry),
+		NewDatum(8, clear),
+	}
+	n, err := NewNetwork(
+		Variable{Name: "Cloudy", States: []Category{cloudy, clear}},
+		Variable{Name: "Rain", States: []Category{raining, dry}, Parents: []string{"Cloudy"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	if err = n.Learn(d, 0); err != nil {
+		t.Fatalf("unexpected error learning: %v", err)
+	}
+	// The datum with no rain state is ignored when learning the probability of rain.
+	expected := map[Category]float64{
+		cloudy: 2.0 / 3.0,
+		clear:  1.0 / 4.0,
+	}
+	for given, e := range expected {
+		actual, err := n.Probability(raining, given)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if math.Abs(e-actual) > 1e-12 {
+			t.Errorf("rain given %v: expected %v, got %v", given, e, actual)
+		}
+	}
+	// But it is still used to learn the probability of cloud.
+	if actual, _ := n.Probability(cloudy); actual != 3.0/8.0 {
+		t.Errorf("expected P(cloudy) to be 3/8, got %v", actual)
+	}
+
+	if err = n.Learn(d, 1); err != nil {
+		t.Fatalf("unexpected error learning: %v", err)
+	}
+	if actual, _ := n.Probability(raining, cloudy); math.Abs(actual-3.0/5.0) > 1e-12 {
+		t.Errorf("expected smoothed P(rain|cloudy) to be 3/5, got %v", actual)
+	}
+}
+
+func TestNetworkErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []Variable
+	}{
+		{
+			name: "no variables",
+		},
+		{
+			name: "cycle",
+			variables: []Variable{
+				{Name: "A", States: []Category{1, 2}, Parents: []string{"B"}},
+				{Name: "B", States: []Category{3, 4}, Parents: []string{"A"}},
+			},
+		},
+		{
+			name: "missing parent",
+			variables: []Variable{
+				{Name: "A", States: []Category{1, 2}, Parents: []string{"C"}},
+			},
+		},
+		{
+			name: "shared category",
+			variables: []Variable{
+				{Name: "A", States: []Category{1, 2}},
+				{Name: "B", States: []Category{2, 3}},
+			},
+		},
+		{
+			name: "no states",
+			variables: []Variable{
+				{Name: "A"},
+			},
+		},
+	}
+	for _, test := range tests {
+		if _, err := NewNetwork(test.variables...); err == nil {
+			t.Errorf("%s: expected an error, but didn't get one", test.name)
+		}
+	}
+
+	n := sprinkler(t)
+	if _, err := n.Probability(raining, wet, notWet); err == nil {
+		t.Errorf("expected an error for conflicting evidence")
+	}
+	if _, err := n.Probability(Category(1)); err == nil {
+		t.Errorf("expected an error for an unknown category")
+	}
+	if _, err := n.Probability(sprinklerOff, dry, notWet, clear); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := n.SetProbabilities("Rain", []float64{1}); err == nil {
+		t.Errorf("expected an error setting a table of the wrong size")
+	}
+	// With no sprinkler and no rain, the grass can't be wet.
+	if _, err := n.Probability(cloudy, sprinklerOff, dry, wet); err == nil {
+		t.Errorf("expected an error for impossible evidence")
+	}
+}
@@ -0,0 +1,111 @@
+package bayes
+
+import "math"
+
+// counter provides the category counts that the probability queries are built on.
+type counter interface {
+	// count returns the number of datums which are members of all of the categories.
+	// With no categories, it returns the total number of datums.
+	count(categories ...Category) int
+}
+
+// Bayes applies Bayes' theorem, P(A|B) = P(B|A)P(A) / P(B), to calculate the posterior
+// probability from the likelihood P(B|A), the prior P(A) and the evidence P(B).
+// If the evidence is zero, the posterior is undefined and 0 is returned.
+func Bayes(likelihood, prior, evidence float64) float64 {
+	if evidence == 0 {
+		return 0
+	}
+	return likelihood * prior / evidence
+}
+
+// Count returns the number of datums which are members of all of the categories.
+func (d Data) Count(categories ...Category) int {
+	return d.count(categories...)
+}
+
+// Joint returns the probability of the data being in all of the categories at once.
+func (d Data) Joint(categories ...Category) float64 {
+	return joint(d, categories...)
+}
+
+// Marginal returns the probability of the data being in a category, regardless of any other
+// categories the data is in.
+func (d Data) Marginal(is Category) float64 {
+	return joint(d, is)
+}
+
+// Posterior calculates the probability of the data being in a category given other categories
+// using Bayes' theorem.
+func (d Data) Posterior(is Category, given ...Category) float64 {
+	return posterior(d, is, given...)
+}
+
+// ConditionallyIndependent returns true when categories a and b are independent once the given
+// categories are known, i.e. P(a, b | given) = P(a | given) P(b | given) within the tolerance.
+func (d Data) ConditionallyIndependent(a, b Category, tolerance float64, given ...Category) bool {
+	return conditionallyIndependent(d, a, b, tolerance, given...)
+}
+
+// Confidence of the association rule antecedent => consequent, i.e. the proportion of data in the
+// antecedent categories which is also in the consequent category.
+func (d Data) Confidence(consequent Category, antecedent ...Category) float64 {
+	return conditional(d, consequent, antecedent...)
+}
+
+// Lift of the association rule antecedent => consequent. A lift of 1 shows that the antecedent
+// and consequent are independent, greater than 1 shows that they occur together more often than
+// by chance.
+func (d Data) Lift(consequent Category, antecedent ...Category) float64 {
+	return lift(d, consequent, antecedent...)
+}
+
+func (d Data) count(categories ...Category) int {
+	if len(categories) == 0 {
+		return len(d)
+	}
+	var n int
+	for _, dm := range d {
+		if matches(dm, categories...) {
+			n++
+		}
+	}
+	return n
+}
+
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+func joint(c counter, categories ...Category) float64 {
+	return ratio(c.count(categories...), c.count())
+}
+
+func conditional(c counter, is Category, given ...Category) float64 {
+	return ratio(c.count(append([]Category{is}, given...)...), c.count(given...))
+}
+
+func posterior(c counter, is Category, given ...Category) float64 {
+	likelihood := ratio(c.count(append([]Category{is}, given...)...), c.count(is))
+	prior := joint(c, is)
+	evidence := joint(c, given...)
+	return Bayes(likelihood, prior, evidence)
+}
+
+func conditionallyIndependent(c counter, a, b Category, tolerance float64, given ...Category) bool {
+	pab := ratio(c.count(append([]Category{a, b}, given...)...), c.count(given...))
+	pa := conditional(c, a, given...)
+	pb := conditional(c, b, given...)
+	return math.Abs(pab-(pa*pb)) <= tolerance
+}
+
+func lift(c counter, consequent Category, antecedent ...Category) float64 {
+	p := joint(c, consequent)
+	if p == 0 {
+		return 0
+	}
+	return conditional(c, consequent, antecedent...) / p
+}
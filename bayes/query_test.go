@@ -0,0 +1,127 @@
+package bayes
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	categoryBrownHair Category = iota + 1
+	categoryBlondeHair
+	categoryBlueEyes
+	categoryBrownEyes
+	categoryTall
+)
+
+func people() Data {
+	return []Datum{
+		NewDatum("John", categoryBlueEyes, categoryBlondeHair, categoryTall),
+		NewDatum("Jane", categoryBlueEyes, categoryBlondeHair),
+		NewDatum("Janet", categoryBlueEyes, categoryBrownHair, categoryTall),
+		NewDatum("Jim", categoryBlueEyes, categoryBrownHair),
+		NewDatum("June", categoryBrownEyes, categoryBrownHair),
+	}
+}
+
+func TestBayes(t *testing.T) {
+	tests := []struct {
+		name                        string
+		likelihood, prior, evidence float64
+		expected                    float64
+	}{
+		{
+			name:       "rare condition with accurate test",
+			likelihood: 0.9,
+			prior:      0.01,
+			evidence:   0.9*0.01 + 0.1*0.99,
+			expected:   0.9 * 0.01 / (0.9*0.01 + 0.1*0.99),
+		},
+		{
+			name:       "zero evidence",
+			likelihood: 0.5,
+			prior:      0.5,
+			evidence:   0,
+			expected:   0,
+		},
+	}
+
+	for _, test := range tests {
+		actual := Bayes(test.likelihood, test.prior, test.evidence)
+		if math.Abs(test.expected-actual) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestQueries(t *testing.T) {
+	d := people()
+	tests := []struct {
+		name     string
+		query    func() float64
+		expected float64
+	}{
+		{
+			name:     "count of all data",
+			query:    func() float64 { return float64(d.Count()) },
+			expected: 5,
+		},
+		{
+			name:     "count of blue eyes and blonde hair",
+			query:    func() float64 { return float64(d.Count(categoryBlueEyes, categoryBlondeHair)) },
+			expected: 2,
+		},
+		{
+			name:     "joint probability of blue eyes and blonde hair",
+			query:    func() float64 { return d.Joint(categoryBlueEyes, categoryBlondeHair) },
+			expected: 0.4,
+		},
+		{
+			name:     "marginal probability of blue eyes",
+			query:    func() float64 { return d.Marginal(categoryBlueEyes) },
+			expected: 0.8,
+		},
+		{
+			name:     "posterior matches conditional probability",
+			query:    func() float64 { return d.Posterior(categoryBlondeHair, categoryBlueEyes) },
+			expected: d.Probability(categoryBlondeHair, categoryBlueEyes),
+		},
+		{
+			name:     "posterior of a missing category",
+			query:    func() float64 { return d.Posterior(Category(100), categoryBlueEyes) },
+			expected: 0,
+		},
+		{
+			name:     "confidence that blue eyes implies blonde hair",
+			query:    func() float64 { return d.Confidence(categoryBlondeHair, categoryBlueEyes) },
+			expected: 0.5,
+		},
+		{
+			name:     "lift of blue eyes to blonde hair",
+			query:    func() float64 { return d.Lift(categoryBlondeHair, categoryBlueEyes) },
+			expected: 0.5 / 0.4,
+		},
+		{
+			name:     "lift of a missing category",
+			query:    func() float64 { return d.Lift(Category(100), categoryBlueEyes) },
+			expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		actual := test.query()
+		if math.Abs(test.expected-actual) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestConditionallyIndependent(t *testing.T) {
+	d := people()
+	if d.ConditionallyIndependent(categoryBlondeHair, categoryBlueEyes, 1e-9) {
+		t.Errorf("expected blonde hair and blue eyes to be dependent")
+	}
+	// Everyone with blue eyes is equally likely to be tall, regardless of hair colour.
+	if !d.ConditionallyIndependent(categoryBlondeHair, categoryTall, 1e-9, categoryBlueEyes) {
+		t.Errorf("expected blonde hair and height to be independent given blue eyes")
+	}
+}
@@ -0,0 +1,95 @@
+package calculus
+
+import (
+	"math"
+)
+
+// ForwardDifference returns the slope of f at point x by measuring the slope from x to x + h.
+// The truncation error is proportional to h.
+func ForwardDifference(x, h float64, f func(x float64) (y float64)) float64 {
+	return (f(x+h) - f(x)) / h
+}
+
+// BackwardDifference returns the slope of f at point x by measuring the slope from x - h to x.
+// The truncation error is proportional to h.
+func BackwardDifference(x, h float64, f func(x float64) (y float64)) float64 {
+	return (f(x) - f(x-h)) / h
+}
+
+// CentralDifference returns the slope of f at point x by measuring the slope from x - h to x + h.
+// The truncation error is proportional to h².
+func CentralDifference(x, h float64, f func(x float64) (y float64)) float64 {
+	return (f(x+h) - f(x-h)) / (2 * h)
+}
+
+// FivePoint returns the slope of f at point x using the five-point stencil x - 2h, x - h, x + h
+// and x + 2h. The truncation error is proportional to h⁴.
+func FivePoint(x, h float64, f func(x float64) (y float64)) float64 {
+	return (-f(x+2*h) + 8*f(x+h) - 8*f(x-h) + f(x-2*h)) / (12 * h)
+}
+
+// SecondDerivative returns the second derivative of f at point x using the central difference
+// of x - h, x and x + h. The truncation error is proportional to h².
+func SecondDerivative(x, h float64, f func(x float64) (y float64)) float64 {
+	return (f(x+h) - 2*f(x) + f(x-h)) / (h * h)
+}
+
+// SecondDerivativeFivePoint returns the second derivative of f at point x using the five-point
+// stencil. The truncation error is proportional to h⁴.
+func SecondDerivativeFivePoint(x, h float64, f func(x float64) (y float64)) float64 {
+	return (-f(x+2*h) + 16*f(x+h) - 30*f(x) + 16*f(x-h) - f(x-2*h)) / (12 * h * h)
+}
+
+// StepSize returns a step size for x which balances truncation error against floating point
+// rounding error for a difference formula whose truncation error is proportional to h^order,
+// e.g. 1 for ForwardDifference, 2 for CentralDifference and 4 for FivePoint.
+func StepSize(x float64, order int) float64 {
+	const epsilon = 2.220446049250313e-16
+	h := math.Pow(epsilon, 1/float64(order+1)) * math.Max(math.Abs(x), 1)
+	// Make sure that x + h is exactly representable, so that the step is exactly h.
+	return (x + h) - x
+}
+
+// Derivative returns the slope of f at point x, and an estimate of its error, using Richardson
+// extrapolation of central differences, starting from an automatically selected step size.
+func Derivative(x float64, f func(x float64) (y float64)) (d, e float64) {
+	return Richardson(x, 0.1*math.Max(math.Abs(x), 1), f)
+}
+
+// Richardson returns the slope of f at point x, and an estimate of its error, by extrapolating
+// central differences with successively smaller steps, starting from h, towards a step of zero.
+// The step which gives the lowest error estimate is used.
+// See Ridders' method in Numerical Recipes in C, section 5.7.
+func Richardson(x, h float64, f func(x float64) (y float64)) (d, e float64) {
+	const (
+		shrink = 1.4
+		steps  = 10
+		// Stop when the error is more than safe times worse than the best so far.
+		safe = 2.0
+	)
+	tableau := make([][]float64, steps)
+	for i := range tableau {
+		tableau[i] = make([]float64, steps)
+	}
+	tableau[0][0] = CentralDifference(x, h, f)
+	d, e = tableau[0][0], math.MaxFloat64
+	for i := 1; i < steps; i++ {
+		h /= shrink
+		tableau[0][i] = CentralDifference(x, h, f)
+		factor := shrink * shrink
+		for j := 1; j <= i; j++ {
+			// Each extrapolation removes the next highest order of the error.
+			tableau[j][i] = (tableau[j-1][i]*factor - tableau[j-1][i-1]) / (factor - 1)
+			factor *= shrink * shrink
+			errt := math.Max(math.Abs(tableau[j][i]-tableau[j-1][i]), math.Abs(tableau[j][i]-tableau[j-1][i-1]))
+			if errt <= e {
+				d, e = tableau[j][i], errt
+			}
+		}
+		// Higher orders are getting worse, so give up.
+		if math.Abs(tableau[i][i]-tableau[i-1][i-1]) >= safe*e {
+			break
+		}
+	}
+	return
+}
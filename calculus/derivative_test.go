@@ -0,0 +1,102 @@
+package calculus
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDifferences(t *testing.T) {
+	f := func(x float64) float64 { return math.Sin(x) }
+	x, expected := 1.0, math.Cos(1.0)
+
+	tests := []struct {
+		name      string
+		actual    float64
+		tolerance float64
+	}{
+		{
+			name:      "forward",
+			actual:    ForwardDifference(x, StepSize(x, 1), f),
+			tolerance: 1e-7,
+		},
+		{
+			name:      "backward",
+			actual:    BackwardDifference(x, StepSize(x, 1), f),
+			tolerance: 1e-7,
+		},
+		{
+			name:      "central",
+			actual:    CentralDifference(x, StepSize(x, 2), f),
+			tolerance: 1e-10,
+		},
+		{
+			name:      "five point",
+			actual:    FivePoint(x, StepSize(x, 4), f),
+			tolerance: 1e-12,
+		},
+	}
+
+	for _, test := range tests {
+		if math.Abs(test.actual-expected) > test.tolerance {
+			t.Errorf("%s: expected %v, got %v", test.name, expected, test.actual)
+		}
+	}
+}
+
+func TestBackwardDifferenceMatchesTangentSlope(t *testing.T) {
+	f := func(x float64) float64 { return x * x * x }
+	if expected, actual := TangentSlope(2, 0.5, f).M, BackwardDifference(2, 0.5, f); expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestSecondDerivative(t *testing.T) {
+	f := func(x float64) float64 { return math.Exp(2 * x) }
+	x, expected := 0.5, 4*math.Exp(1)
+
+	if actual := SecondDerivative(x, 1e-4, f); math.Abs(actual-expected) > 1e-5 {
+		t.Errorf("central: expected %v, got %v", expected, actual)
+	}
+	if actual := SecondDerivativeFivePoint(x, 1e-3, f); math.Abs(actual-expected) > 1e-7 {
+		t.Errorf("five point: expected %v, got %v", expected, actual)
+	}
+}
+
+func TestRichardson(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        func(float64) float64
+		x        float64
+		expected float64
+	}{
+		{
+			name:     "exp",
+			f:        math.Exp,
+			x:        1.5,
+			expected: math.Exp(1.5),
+		},
+		{
+			name:     "polynomial",
+			f:        func(x float64) float64 { return 3*x*x*x - 2*x },
+			x:        -20,
+			expected: 9*400 - 2,
+		},
+		{
+			name:     "tan",
+			f:        math.Tan,
+			x:        1.0,
+			expected: 1 / (math.Cos(1.0) * math.Cos(1.0)),
+		},
+	}
+
+	for _, test := range tests {
+		d, e := Derivative(test.x, test.f)
+		actualError := math.Abs(d - test.expected)
+		if actualError > 1e-9*math.Max(1, math.Abs(test.expected)) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, d)
+		}
+		if actualError > 10*e+1e-12 {
+			t.Errorf("%s: error estimate %v was much lower than the actual error %v", test.name, e, actualError)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package calculus
+
+import (
+	"errors"
+)
+
+// ErrNotBracketed is an error for when the interval provided to a solver does not contain a
+// solution, e.g. the function has the same sign at both ends of the interval, so there's no root
+// to find.
+var ErrNotBracketed = errors.New("calculus: the interval does not bracket a solution")
+
+// ErrMaxIterations is an error for when a solver did not converge to within the tolerance before
+// reaching the maximum number of iterations.
+var ErrMaxIterations = errors.New("calculus: maximum iterations reached without converging")
+
+// ErrZeroDerivative is an error for when a solver cannot continue because the slope of the
+// function is zero.
+var ErrZeroDerivative = errors.New("calculus: zero derivative")
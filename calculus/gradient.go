@@ -0,0 +1,145 @@
+package calculus
+
+import (
+	"sync"
+)
+
+// Gradient returns the partial derivatives of f at point x using central differences.
+func Gradient(x []float64, f func(x []float64) (y float64)) []float64 {
+	return FiniteDifference{}.Gradient(x, f)
+}
+
+// Jacobian returns the matrix of partial derivatives of f at point x using central differences.
+// Each row of the matrix contains the partial derivatives of an output of f.
+func Jacobian(x []float64, f func(x []float64) (y []float64)) [][]float64 {
+	return FiniteDifference{}.Jacobian(x, f)
+}
+
+// Hessian returns the matrix of second partial derivatives of f at point x using central differences.
+func Hessian(x []float64, f func(x []float64) (y float64)) [][]float64 {
+	return FiniteDifference{}.Hessian(x, f)
+}
+
+// FiniteDifference calculates derivatives of multivariate functions using central differences.
+type FiniteDifference struct {
+	// Scale multiplies the automatically selected step size of each dimension, e.g. to account for
+	// dimensions with very different ranges. If nil, the step size isn't scaled. Dimensions beyond
+	// the end of Scale aren't scaled.
+	Scale []float64
+	// Workers is the number of goroutines used to evaluate the function. Zero or one evaluates
+	// the function sequentially. When greater than one, the function must be safe to call from
+	// multiple goroutines.
+	Workers int
+}
+
+// Gradient returns the partial derivatives of f at point x.
+func (fd FiniteDifference) Gradient(x []float64, f func(x []float64) (y float64)) []float64 {
+	op := make([]float64, len(x))
+	fd.each(x, len(x), func(i int, xc []float64) {
+		h := fd.step(x, i, 2)
+		xc[i] = x[i] + h
+		forward := f(xc)
+		xc[i] = x[i] - h
+		backward := f(xc)
+		xc[i] = x[i]
+		op[i] = (forward - backward) / (2 * h)
+	})
+	return op
+}
+
+// Jacobian returns the matrix of partial derivatives of f at point x. Each row of the matrix
+// contains the partial derivatives of an output of f.
+func (fd FiniteDifference) Jacobian(x []float64, f func(x []float64) (y []float64)) [][]float64 {
+	columns := make([][]float64, len(x))
+	fd.each(x, len(x), func(i int, xc []float64) {
+		h := fd.step(x, i, 2)
+		xc[i] = x[i] + h
+		forward := f(xc)
+		xc[i] = x[i] - h
+		backward := f(xc)
+		xc[i] = x[i]
+		column := make([]float64, len(forward))
+		for j := range forward {
+			column[j] = (forward[j] - backward[j]) / (2 * h)
+		}
+		columns[i] = column
+	})
+	if len(columns) == 0 {
+		return nil
+	}
+	op := make([][]float64, len(columns[0]))
+	for j := range op {
+		op[j] = make([]float64, len(x))
+		for i, column := range columns {
+			op[j][i] = column[j]
+		}
+	}
+	return op
+}
+
+// Hessian returns the matrix of second partial derivatives of f at point x.
+func (fd FiniteDifference) Hessian(x []float64, f func(x []float64) (y float64)) [][]float64 {
+	op := make([][]float64, len(x))
+	for i := range op {
+		op[i] = make([]float64, len(x))
+	}
+	centre := f(x)
+	fd.each(x, len(x), func(i int, xc []float64) {
+		hi := fd.step(x, i, 3)
+		xc[i] = x[i] + hi
+		forward := f(xc)
+		xc[i] = x[i] - hi
+		backward := f(xc)
+		xc[i] = x[i]
+		op[i][i] = (forward - 2*centre + backward) / (hi * hi)
+		// The matrix is symmetric, so only calculate the upper triangle.
+		for j := i + 1; j < len(x); j++ {
+			hj := fd.step(x, j, 3)
+			var sum float64
+			for _, s := range [][2]float64{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}} {
+				xc[i], xc[j] = x[i]+s[0]*hi, x[j]+s[1]*hj
+				sum += s[0] * s[1] * f(xc)
+			}
+			xc[i], xc[j] = x[i], x[j]
+			op[i][j] = sum / (4 * hi * hj)
+			op[j][i] = op[i][j]
+		}
+	})
+	return op
+}
+
+func (fd FiniteDifference) step(x []float64, i, order int) float64 {
+	h := StepSize(x[i], order)
+	if i < len(fd.Scale) {
+		h *= fd.Scale[i]
+	}
+	return h
+}
+
+// each calls fn for every index up to n, passing a copy of x which fn can modify but must restore.
+func (fd FiniteDifference) each(x []float64, n int, fn func(i int, xc []float64)) {
+	if fd.Workers <= 1 {
+		xc := append([]float64(nil), x...)
+		for i := 0; i < n; i++ {
+			fn(i, xc)
+		}
+		return
+	}
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(fd.Workers)
+	for w := 0; w < fd.Workers; w++ {
+		go func() {
+			defer wg.Done()
+			xc := append([]float64(nil), x...)
+			for i := range indices {
+				fn(i, xc)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
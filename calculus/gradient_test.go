@@ -0,0 +1,104 @@
+package calculus
+
+import (
+	"math"
+	"testing"
+)
+
+func rosenbrock(x []float64) float64 {
+	return (1-x[0])*(1-x[0]) + 100*(x[1]-x[0]*x[0])*(x[1]-x[0]*x[0])
+}
+
+func TestGradient(t *testing.T) {
+	x := []float64{-1.2, 1.0}
+	expected := []float64{
+		-2*(1-x[0]) - 400*x[0]*(x[1]-x[0]*x[0]),
+		200 * (x[1] - x[0]*x[0]),
+	}
+	tests := []struct {
+		name string
+		fd   FiniteDifference
+	}{
+		{
+			name: "sequential",
+		},
+		{
+			name: "parallel",
+			fd:   FiniteDifference{Workers: 4},
+		},
+		{
+			name: "scaled",
+			fd:   FiniteDifference{Scale: []float64{0.5, 2}},
+		},
+		{
+			name: "partially scaled",
+			fd:   FiniteDifference{Scale: []float64{0.5}},
+		},
+	}
+
+	for _, test := range tests {
+		actual := test.fd.Gradient(x, rosenbrock)
+		if !approximately(actual, expected, 1e-6) {
+			t.Errorf("%s: expected %v, got %v", test.name, expected, actual)
+		}
+	}
+	if actual := Gradient(x, rosenbrock); !approximately(actual, expected, 1e-6) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if x[0] != -1.2 || x[1] != 1.0 {
+		t.Errorf("expected the input to be unchanged, but got %v", x)
+	}
+}
+
+func TestJacobian(t *testing.T) {
+	f := func(x []float64) []float64 {
+		return []float64{
+			x[0] * x[1],
+			math.Sin(x[0]) + x[2],
+		}
+	}
+	x := []float64{1, 2, 3}
+	expected := [][]float64{
+		{x[1], x[0], 0},
+		{math.Cos(x[0]), 0, 1},
+	}
+	for _, workers := range []int{0, 3} {
+		actual := FiniteDifference{Workers: workers}.Jacobian(x, f)
+		if len(actual) != len(expected) {
+			t.Fatalf("expected %d rows, got %d", len(expected), len(actual))
+		}
+		for i := range expected {
+			if !approximately(actual[i], expected[i], 1e-8) {
+				t.Errorf("workers %d: row %d: expected %v, got %v", workers, i, expected[i], actual[i])
+			}
+		}
+	}
+}
+
+func TestHessian(t *testing.T) {
+	x := []float64{1, 1}
+	expected := [][]float64{
+		{1200*x[0]*x[0] - 400*x[1] + 2, -400 * x[0]},
+		{-400 * x[0], 200},
+	}
+	for _, workers := range []int{0, 2} {
+		actual := FiniteDifference{Workers: workers}.Hessian(x, rosenbrock)
+		for i := range expected {
+			if !approximately(actual[i], expected[i], 1e-3) {
+				t.Errorf("workers %d: row %d: expected %v, got %v", workers, i, expected[i], actual[i])
+			}
+		}
+	}
+}
+
+func approximately(a, b []float64, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tolerance*math.Max(1, math.Abs(b[i])) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,219 @@
+package calculus
+
+import (
+	"errors"
+	"math"
+
+	"github.com/a-h/ml/random"
+)
+
+// Trapezoid integrates f from a to b using the trapezoid rule with n intervals.
+// The error is proportional to 1/n².
+func Trapezoid(a, b float64, n int, f func(x float64) (y float64)) float64 {
+	if n < 1 {
+		n = 1
+	}
+	h := (b - a) / float64(n)
+	sum := (f(a) + f(b)) / 2
+	for i := 1; i < n; i++ {
+		sum += f(a + float64(i)*h)
+	}
+	return sum * h
+}
+
+// Simpson integrates f from a to b using Simpson's rule with n intervals. If n is odd, it is
+// increased by one. The error is proportional to 1/n⁴.
+func Simpson(a, b float64, n int, f func(x float64) (y float64)) float64 {
+	if n < 2 {
+		n = 2
+	}
+	if n%2 == 1 {
+		n++
+	}
+	h := (b - a) / float64(n)
+	sum := f(a) + f(b)
+	for i := 1; i < n; i++ {
+		x := a + float64(i)*h
+		if i%2 == 1 {
+			sum += 4 * f(x)
+		} else {
+			sum += 2 * f(x)
+		}
+	}
+	return sum * h / 3
+}
+
+// GaussLegendre integrates f from a to b using n-point Gauss-Legendre quadrature, which is exact
+// for polynomials of degree 2n - 1 or lower.
+func GaussLegendre(a, b float64, n int, f func(x float64) (y float64)) float64 {
+	nodes, weights := gaussLegendre(n)
+	mid, half := (a+b)/2, (b-a)/2
+	var sum float64
+	for i, x := range nodes {
+		sum += weights[i] * f(mid+half*x)
+	}
+	return sum * half
+}
+
+// gaussLegendre returns the nodes and weights of n-point Gauss-Legendre quadrature on the
+// interval -1 to 1, by finding the roots of the Legendre polynomial with Newton's method.
+// See Numerical Recipes in C, section 4.5.
+func gaussLegendre(n int) (nodes, weights []float64) {
+	if n < 1 {
+		n = 1
+	}
+	nodes, weights = make([]float64, n), make([]float64, n)
+	for i := 0; i < (n+1)/2; i++ {
+		// Initial approximation of the root.
+		z := math.Cos(math.Pi * (float64(i) + 0.75) / (float64(n) + 0.5))
+		var dp float64
+		for iteration := 0; iteration < 100; iteration++ {
+			// Calculate the Legendre polynomial at z using the recurrence relation.
+			p1, p2 := 1.0, 0.0
+			for j := 1; j <= n; j++ {
+				p1, p2 = ((2*float64(j)-1)*z*p1-(float64(j)-1)*p2)/float64(j), p1
+			}
+			dp = float64(n) * (z*p1 - p2) / (z*z - 1)
+			previous := z
+			z -= p1 / dp
+			if math.Abs(z-previous) < 1e-15 {
+				break
+			}
+		}
+		nodes[i], nodes[n-1-i] = -z, z
+		weights[i] = 2 / ((1 - z*z) * dp * dp)
+		weights[n-1-i] = weights[i]
+	}
+	return
+}
+
+// maxDepth, maxHalvings and maxEvaluations limit the work done by AdaptiveSimpson and Romberg, since each
+// level of recursion or halving can double the number of function evaluations.
+const (
+	maxDepth       = 50
+	maxHalvings    = 20
+	maxEvaluations = 1 << 20
+)
+
+// integrationTolerance scales the tolerance by the magnitude of the area, so that the tolerance is
+// absolute for small areas, and relative for areas greater than 1, which can't be calculated to a
+// fixed absolute accuracy due to rounding errors.
+func (s Solver) integrationTolerance(area float64) float64 {
+	return s.tolerance() * math.Max(1, math.Abs(area))
+}
+
+// AdaptiveSimpson integrates f from a to b using Simpson's rule, recursively splitting intervals
+// until the estimated error of each is within the tolerance, relative to the area if it's greater
+// than 1. The depth of recursion is limited to MaxIterations (at most 50), and the number of
+// evaluations of f to around a million, after which ErrMaxIterations is returned along with the
+// estimate.
+func (s Solver) AdaptiveSimpson(a, b float64, f func(x float64) (y float64)) (area float64, err error) {
+	fa, fm, fb := f(a), f((a+b)/2), f(b)
+	whole := (b - a) * (fa + 4*fm + fb) / 6
+	depth := s.maxIterations()
+	if depth > maxDepth {
+		depth = maxDepth
+	}
+	as := &adaptiveSimpson{f: f, evaluations: 3}
+	area, ok := as.integrate(a, b, fa, fm, fb, whole, s.integrationTolerance(whole), depth)
+	if !ok {
+		err = ErrMaxIterations
+	}
+	return
+}
+
+type adaptiveSimpson struct {
+	f           func(x float64) (y float64)
+	evaluations int
+}
+
+func (as *adaptiveSimpson) integrate(a, b, fa, fm, fb, whole, tolerance float64, depth int) (float64, bool) {
+	m := (a + b) / 2
+	lm, rm := (a+m)/2, (m+b)/2
+	flm, frm := as.f(lm), as.f(rm)
+	as.evaluations += 2
+	left := (m - a) * (fa + 4*flm + fm) / 6
+	right := (b - m) * (fm + 4*frm + fb) / 6
+	delta := left + right - whole
+	if math.Abs(delta) <= 15*tolerance {
+		// Richardson extrapolation of the two estimates.
+		return left + right + delta/15, true
+	}
+	if depth <= 0 || as.evaluations >= maxEvaluations {
+		return left + right, false
+	}
+	l, lok := as.integrate(a, m, fa, flm, fm, left, tolerance/2, depth-1)
+	r, rok := as.integrate(m, b, fm, frm, fb, right, tolerance/2, depth-1)
+	return l + r, lok && rok
+}
+
+// Romberg integrates f from a to b by Richardson extrapolation of the trapezoid rule with
+// successively halved intervals, until successive estimates are within the tolerance, relative to
+// the area if it's greater than 1. After MaxIterations halvings (at most 20), ErrMaxIterations is
+// returned along with the estimate.
+func (s Solver) Romberg(a, b float64, f func(x float64) (y float64)) (area float64, err error) {
+	previous := []float64{(b - a) * (f(a) + f(b)) / 2}
+	h := b - a
+	halvings := s.maxIterations()
+	if halvings > maxHalvings {
+		halvings = maxHalvings
+	}
+	for i := 1; i <= halvings; i++ {
+		h /= 2
+		// Add the midpoints of the previous intervals to the trapezoid estimate.
+		var sum float64
+		for k := 1; k < 1<<uint(i); k += 2 {
+			sum += f(a + float64(k)*h)
+		}
+		current := make([]float64, i+1)
+		current[0] = previous[0]/2 + h*sum
+		factor := 1.0
+		for j := 1; j <= i; j++ {
+			factor *= 4
+			current[j] = current[j-1] + (current[j-1]-previous[j-1])/(factor-1)
+		}
+		if math.Abs(current[i]-previous[i-1]) <= s.integrationTolerance(current[i]) {
+			return current[i], nil
+		}
+		previous = current
+	}
+	return previous[len(previous)-1], ErrMaxIterations
+}
+
+// MonteCarlo integrates f over the region between min and max in each dimension by averaging f at
+// randomly sampled points. It returns the estimate and its standard error, which is proportional
+// to 1 / √samples.
+func MonteCarlo(min, max []float64, samples int, f func(x []float64) (y float64)) (area, standardError float64, err error) {
+	if len(min) != len(max) {
+		err = errors.New("calculus: mismatched lengths of min and max")
+		return
+	}
+	if len(min) == 0 {
+		err = errors.New("calculus: no dimensions to integrate")
+		return
+	}
+	if samples < 2 {
+		err = errors.New("calculus: at least two samples are required")
+		return
+	}
+	volume := 1.0
+	for i := range min {
+		volume *= max[i] - min[i]
+	}
+	x := make([]float64, len(min))
+	var sum, sumOfSquares float64
+	for s := 0; s < samples; s++ {
+		for i := range x {
+			x[i] = random.Float64(min[i], max[i])
+		}
+		y := f(x)
+		sum += y
+		sumOfSquares += y * y
+	}
+	n := float64(samples)
+	mean := sum / n
+	variance := (sumOfSquares - n*mean*mean) / (n - 1)
+	area = volume * mean
+	standardError = volume * math.Sqrt(math.Max(variance, 0)/n)
+	return
+}
@@ -0,0 +1,160 @@
+package calculus
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestIntegration(t *testing.T) {
+	// The integral of a standard normal distribution from -1 to 1.
+	gaussian := func(x float64) float64 { return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi) }
+	expected := math.Erf(1 / math.Sqrt2)
+	s := Solver{Tolerance: 1e-12}
+
+	tests := []struct {
+		name      string
+		integrate func() (float64, error)
+		tolerance float64
+	}{
+		{
+			name:      "trapezoid",
+			integrate: func() (float64, error) { return Trapezoid(-1, 1, 1000, gaussian), nil },
+			tolerance: 1e-6,
+		},
+		{
+			name:      "simpson",
+			integrate: func() (float64, error) { return Simpson(-1, 1, 999, gaussian), nil },
+			tolerance: 1e-12,
+		},
+		{
+			name:      "gauss-legendre",
+			integrate: func() (float64, error) { return GaussLegendre(-1, 1, 10, gaussian), nil },
+			tolerance: 1e-12,
+		},
+		{
+			name:      "adaptive simpson",
+			integrate: func() (float64, error) { return s.AdaptiveSimpson(-1, 1, gaussian) },
+			tolerance: 1e-11,
+		},
+		{
+			name:      "romberg",
+			integrate: func() (float64, error) { return s.Romberg(-1, 1, gaussian) },
+			tolerance: 1e-11,
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := test.integrate()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if math.Abs(actual-expected) > test.tolerance {
+			t.Errorf("%s: expected %v, got %v", test.name, expected, actual)
+		}
+	}
+}
+
+func TestGaussLegendreIsExactForPolynomials(t *testing.T) {
+	// 3 points is exact up to degree 5.
+	f := func(x float64) float64 { return 6*math.Pow(x, 5) - 2*x*x + 1 }
+	// ∫ from 0 to 2 = 2⁶ - 2(2³)/3 + 2
+	expected := 64 - 16.0/3 + 2
+	if actual := GaussLegendre(0, 2, 3, f); math.Abs(actual-expected) > 1e-12 {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestIntegrationMaxIterations(t *testing.T) {
+	s := Solver{Tolerance: 1e-15, MaxIterations: 2}
+	if _, err := s.AdaptiveSimpson(0, 10, math.Sin); err != ErrMaxIterations {
+		t.Errorf("adaptive simpson: expected %v, got %v", ErrMaxIterations, err)
+	}
+	if _, err := s.Romberg(0, 10, math.Sin); err != ErrMaxIterations {
+		t.Errorf("romberg: expected %v, got %v", ErrMaxIterations, err)
+	}
+}
+
+func TestIntegrationOfLargeAreas(t *testing.T) {
+	f := func(x float64) float64 { return math.Exp(-x/1000) * 1000 }
+	expected := 1e6 * (1 - math.Exp(-1))
+	s := Solver{}
+	tests := []struct {
+		name      string
+		integrate func() (float64, error)
+	}{
+		{
+			name:      "adaptive simpson",
+			integrate: func() (float64, error) { return s.AdaptiveSimpson(0, 1000, f) },
+		},
+		{
+			name:      "romberg",
+			integrate: func() (float64, error) { return s.Romberg(0, 1000, f) },
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := test.integrate()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if math.Abs(actual-expected)/expected > 1e-11 {
+			t.Errorf("%s: expected %v, got %v", test.name, expected, actual)
+		}
+	}
+}
+
+func TestIntegrationLimitsEvaluations(t *testing.T) {
+	// Noise never converges, so the number of evaluations must be limited.
+	var evaluations int
+	noise := func(x float64) float64 {
+		evaluations++
+		return rand.Float64()
+	}
+	s := Solver{}
+	if _, err := s.AdaptiveSimpson(0, 1, noise); err != ErrMaxIterations {
+		t.Errorf("adaptive simpson: expected %v, got %v", ErrMaxIterations, err)
+	}
+	if evaluations > 1<<21 {
+		t.Errorf("adaptive simpson: expected at most %d evaluations, got %d", 1<<21, evaluations)
+	}
+	evaluations = 0
+	if _, err := s.Romberg(0, 1, noise); err != ErrMaxIterations {
+		t.Errorf("romberg: expected %v, got %v", ErrMaxIterations, err)
+	}
+	if evaluations > 1<<21 {
+		t.Errorf("romberg: expected at most %d evaluations, got %d", 1<<21, evaluations)
+	}
+}
+
+func TestMonteCarlo(t *testing.T) {
+	// The area of a unit circle, from the square which contains it.
+	circle := func(x []float64) float64 {
+		if x[0]*x[0]+x[1]*x[1] <= 1 {
+			return 1
+		}
+		return 0
+	}
+	area, standardError, err := MonteCarlo([]float64{-1, -1}, []float64{1, 1}, 100000, circle)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(area-math.Pi) > 5*standardError {
+		t.Errorf("expected %v, got %v with standard error %v", math.Pi, area, standardError)
+	}
+	if standardError <= 0 || standardError > 0.01 {
+		t.Errorf("unexpected standard error %v", standardError)
+	}
+
+	if _, _, err := MonteCarlo([]float64{0}, []float64{1, 1}, 100, circle); err == nil {
+		t.Errorf("expected an error for mismatched lengths")
+	}
+	if _, _, err := MonteCarlo(nil, nil, 100, circle); err == nil {
+		t.Errorf("expected an error for no dimensions")
+	}
+	if _, _, err := MonteCarlo([]float64{0}, []float64{1}, 1, circle); err == nil {
+		t.Errorf("expected an error for too few samples")
+	}
+}
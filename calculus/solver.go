@@ -0,0 +1,312 @@
+package calculus
+
+import (
+	"math"
+)
+
+// Solver finds roots, minima and integrals of functions of one variable.
+type Solver struct {
+	// Tolerance is the absolute accuracy required of the solution, or for integrals with an area
+	// greater than 1, the relative accuracy. If zero, 1e-12 is used.
+	Tolerance float64
+	// MaxIterations before giving up with ErrMaxIterations. If zero, 100 is used.
+	MaxIterations int
+}
+
+func (s Solver) tolerance() float64 {
+	if s.Tolerance <= 0 {
+		return 1e-12
+	}
+	return s.Tolerance
+}
+
+func (s Solver) maxIterations() int {
+	if s.MaxIterations <= 0 {
+		return 100
+	}
+	return s.MaxIterations
+}
+
+// Bisection finds a root of f between a and b by repeatedly halving the interval.
+func (s Solver) Bisection(a, b float64, f func(x float64) (y float64)) (x float64, err error) {
+	fa, fb := f(a), f(b)
+	if fa == 0 {
+		return a, nil
+	}
+	if fb == 0 {
+		return b, nil
+	}
+	if sameSign(fa, fb) {
+		return 0, ErrNotBracketed
+	}
+	for i := 0; i < s.maxIterations(); i++ {
+		x = a + (b-a)/2
+		fx := f(x)
+		if fx == 0 || math.Abs(b-a)/2 < s.tolerance() {
+			return x, nil
+		}
+		if sameSign(fx, fa) {
+			a, fa = x, fx
+		} else {
+			b = x
+		}
+	}
+	return x, ErrMaxIterations
+}
+
+// NewtonRaphson finds a root of f, starting from x0, by following the tangent of f to where it
+// crosses zero. If the derivative df is nil, the slope is calculated using TangentSlope.
+func (s Solver) NewtonRaphson(x0 float64, f, df func(x float64) (y float64)) (x float64, err error) {
+	if df == nil {
+		df = func(x float64) float64 {
+			return TangentSlope(x, StepSize(x, 1), f).M
+		}
+	}
+	x = x0
+	for i := 0; i < s.maxIterations(); i++ {
+		fx := f(x)
+		if fx == 0 {
+			return x, nil
+		}
+		m := df(x)
+		if m == 0 {
+			return x, ErrZeroDerivative
+		}
+		step := fx / m
+		x -= step
+		if math.Abs(step) < s.tolerance() {
+			return x, nil
+		}
+	}
+	return x, ErrMaxIterations
+}
+
+// Secant finds a root of f, starting from x0 and x1, by following the line through the two most
+// recent points to where it crosses zero.
+func (s Solver) Secant(x0, x1 float64, f func(x float64) (y float64)) (x float64, err error) {
+	f0, f1 := f(x0), f(x1)
+	for i := 0; i < s.maxIterations(); i++ {
+		if f1 == 0 {
+			return x1, nil
+		}
+		if f1 == f0 {
+			return x1, ErrZeroDerivative
+		}
+		x2 := x1 - f1*(x1-x0)/(f1-f0)
+		if math.Abs(x2-x1) < s.tolerance() {
+			return x2, nil
+		}
+		x0, f0 = x1, f1
+		x1, f1 = x2, f(x2)
+	}
+	return x1, ErrMaxIterations
+}
+
+// Brent finds a root of f between a and b using Brent's method, which combines bisection, the
+// secant method and inverse quadratic interpolation.
+// See Numerical Recipes in C, section 9.3.
+func (s Solver) Brent(a, b float64, f func(x float64) (y float64)) (x float64, err error) {
+	const epsilon = 2.220446049250313e-16
+	fa, fb := f(a), f(b)
+	if sameSign(fa, fb) && fa != 0 && fb != 0 {
+		return 0, ErrNotBracketed
+	}
+	c, fc := b, fb
+	var d, e float64
+	for i := 0; i < s.maxIterations(); i++ {
+		if sameSign(fb, fc) && fb != 0 && fc != 0 {
+			// Rename a, b and c, so that the root is between b and c.
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol := 2*epsilon*math.Abs(b) + 0.5*s.tolerance()
+		xm := 0.5 * (c - b)
+		if math.Abs(xm) <= tol || fb == 0 {
+			return b, nil
+		}
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			// Attempt inverse quadratic interpolation.
+			var p, q float64
+			sr := fb / fa
+			if a == c {
+				p = 2 * xm * sr
+				q = 1 - sr
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = sr * (2*xm*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (sr - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+			if 2*p < math.Min(3*xm*q-math.Abs(tol*q), math.Abs(e*q)) {
+				// Accept the interpolation.
+				e = d
+				d = p / q
+			} else {
+				// Interpolation failed, use bisection.
+				d = xm
+				e = d
+			}
+		} else {
+			// Bounds are decreasing too slowly, use bisection.
+			d = xm
+			e = d
+		}
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else {
+			b += math.Copysign(tol, xm)
+		}
+		fb = f(b)
+	}
+	return b, ErrMaxIterations
+}
+
+// GoldenSection finds the minimum of f between a and b, by narrowing the interval in the golden
+// ratio. f must have a single minimum in the interval. If the minimum is at one end of the
+// interval, ErrNotBracketed is returned along with the end.
+func (s Solver) GoldenSection(a, b float64, f func(x float64) (y float64)) (x, fx float64, err error) {
+	invPhi := (math.Sqrt(5) - 1) / 2
+	if a > b {
+		a, b = b, a
+	}
+	fa, fb := f(a), f(b)
+	lower, upper := a, b
+	c, d := b-invPhi*(b-a), a+invPhi*(b-a)
+	fc, fd := f(c), f(d)
+	err = ErrMaxIterations
+	for i := 0; i < s.maxIterations(); i++ {
+		if math.Abs(b-a) <= s.tolerance() {
+			err = nil
+			break
+		}
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - invPhi*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + invPhi*(b-a)
+			fd = f(d)
+		}
+	}
+	x = (a + b) / 2
+	fx = f(x)
+	return checkMinimum(x, fx, lower, fa, upper, fb, err)
+}
+
+// BrentMinimum finds the minimum of f between a and b using Brent's method, which combines
+// golden section search with parabolic interpolation. f must have a single minimum in the
+// interval. If the minimum is at one end of the interval, ErrNotBracketed is returned along with
+// the end. The accuracy of the result is limited to around 1e-8 relative to x.
+// See Numerical Recipes in C, section 10.2.
+func (s Solver) BrentMinimum(a, b float64, f func(x float64) (y float64)) (x, fx float64, err error) {
+	const (
+		cgold       = 0.3819660
+		sqrtEpsilon = 1.4901161193847656e-08
+	)
+	if a > b {
+		a, b = b, a
+	}
+	fa, fb := f(a), f(b)
+	lower, upper := a, b
+	x = a + cgold*(b-a)
+	w, v := x, x
+	fx = f(x)
+	fw, fv := fx, fx
+	var d, e float64
+	err = ErrMaxIterations
+	for i := 0; i < s.maxIterations(); i++ {
+		xm := 0.5 * (a + b)
+		tol1 := sqrtEpsilon*math.Abs(x) + s.tolerance()/3
+		tol2 := 2 * tol1
+		if math.Abs(x-xm) <= tol2-0.5*(b-a) {
+			err = nil
+			break
+		}
+		golden := true
+		if math.Abs(e) > tol1 {
+			// Try a parabolic fit through x, v and w.
+			r := (x - w) * (fx - fv)
+			q := (x - v) * (fx - fw)
+			p := (x-v)*q - (x-w)*r
+			q = 2 * (q - r)
+			if q > 0 {
+				p = -p
+			}
+			q = math.Abs(q)
+			etemp := e
+			e = d
+			if math.Abs(p) < math.Abs(0.5*q*etemp) && p > q*(a-x) && p < q*(b-x) {
+				d = p / q
+				u := x + d
+				if u-a < tol2 || b-u < tol2 {
+					d = math.Copysign(tol1, xm-x)
+				}
+				golden = false
+			}
+		}
+		if golden {
+			if x >= xm {
+				e = a - x
+			} else {
+				e = b - x
+			}
+			d = cgold * e
+		}
+		var u float64
+		if math.Abs(d) >= tol1 {
+			u = x + d
+		} else {
+			u = x + math.Copysign(tol1, d)
+		}
+		fu := f(u)
+		if fu <= fx {
+			if u >= x {
+				a = x
+			} else {
+				b = x
+			}
+			v, w, x = w, x, u
+			fv, fw, fx = fw, fx, fu
+			continue
+		}
+		if u < x {
+			a = u
+		} else {
+			b = u
+		}
+		if fu <= fw || w == x {
+			v, w = w, u
+			fv, fw = fw, fu
+		} else if fu <= fv || v == x || v == w {
+			v, fv = u, fu
+		}
+	}
+	return checkMinimum(x, fx, lower, fa, upper, fb, err)
+}
+
+// checkMinimum returns ErrNotBracketed if either end of the interval is lower than the minimum
+// that was found.
+func checkMinimum(x, fx, a, fa, b, fb float64, err error) (float64, float64, error) {
+	if fa < fx {
+		return a, fa, ErrNotBracketed
+	}
+	if fb < fx {
+		return b, fb, ErrNotBracketed
+	}
+	return x, fx, err
+}
+
+func sameSign(a, b float64) bool {
+	return (a > 0) == (b > 0)
+}
@@ -0,0 +1,128 @@
+package calculus
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRootFinding(t *testing.T) {
+	// The root of x² - 2 is the square root of 2.
+	f := func(x float64) float64 { return x*x - 2 }
+	df := func(x float64) float64 { return 2 * x }
+	s := Solver{Tolerance: 1e-12}
+
+	tests := []struct {
+		name  string
+		solve func() (float64, error)
+	}{
+		{
+			name:  "bisection",
+			solve: func() (float64, error) { return s.Bisection(0, 2, f) },
+		},
+		{
+			name:  "newton-raphson",
+			solve: func() (float64, error) { return s.NewtonRaphson(1, f, df) },
+		},
+		{
+			name:  "newton-raphson using the tangent slope",
+			solve: func() (float64, error) { return s.NewtonRaphson(1, f, nil) },
+		},
+		{
+			name:  "secant",
+			solve: func() (float64, error) { return s.Secant(0, 2, f) },
+		},
+		{
+			name:  "brent",
+			solve: func() (float64, error) { return s.Brent(0, 2, f) },
+		},
+		{
+			name:  "brent with the bracket reversed",
+			solve: func() (float64, error) { return s.Brent(2, 0, f) },
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := test.solve()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if math.Abs(actual-math.Sqrt2) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", test.name, math.Sqrt2, actual)
+		}
+	}
+}
+
+func TestRootFindingErrors(t *testing.T) {
+	f := func(x float64) float64 { return x*x + 1 }
+	s := Solver{}
+
+	if _, err := s.Bisection(-1, 1, f); err != ErrNotBracketed {
+		t.Errorf("bisection: expected %v, got %v", ErrNotBracketed, err)
+	}
+	if _, err := s.Brent(-1, 1, f); err != ErrNotBracketed {
+		t.Errorf("brent: expected %v, got %v", ErrNotBracketed, err)
+	}
+	if _, err := s.NewtonRaphson(0, f, func(x float64) float64 { return 2 * x }); err != ErrZeroDerivative {
+		t.Errorf("newton-raphson: expected %v, got %v", ErrZeroDerivative, err)
+	}
+	if _, err := s.Secant(-1, 1, f); err != ErrZeroDerivative {
+		t.Errorf("secant: expected %v, got %v", ErrZeroDerivative, err)
+	}
+	limited := Solver{Tolerance: 1e-15, MaxIterations: 3}
+	if _, err := limited.Bisection(0, 2, func(x float64) float64 { return x - math.Pi/2 }); err != ErrMaxIterations {
+		t.Errorf("bisection: expected %v, got %v", ErrMaxIterations, err)
+	}
+	if _, err := limited.NewtonRaphson(2, math.Atan, nil); err != ErrMaxIterations {
+		t.Errorf("newton-raphson: expected %v, got %v", ErrMaxIterations, err)
+	}
+}
+
+func TestMinimisation(t *testing.T) {
+	f := func(x float64) float64 { return (x-1.5)*(x-1.5) + 0.5 }
+	s := Solver{Tolerance: 1e-10}
+
+	x, fx, err := s.GoldenSection(-3, 5, f)
+	if err != nil {
+		t.Errorf("golden section: unexpected error: %v", err)
+	}
+	if math.Abs(x-1.5) > 1e-7 || math.Abs(fx-0.5) > 1e-12 {
+		t.Errorf("golden section: expected minimum of 0.5 at 1.5, got %v at %v", fx, x)
+	}
+
+	x, fx, err = s.BrentMinimum(-3, 5, f)
+	if err != nil {
+		t.Errorf("brent: unexpected error: %v", err)
+	}
+	if math.Abs(x-1.5) > 1e-7 || math.Abs(fx-0.5) > 1e-12 {
+		t.Errorf("brent: expected minimum of 0.5 at 1.5, got %v at %v", fx, x)
+	}
+
+	x, _, err = s.BrentMinimum(0, 10, math.Cos)
+	if err != nil {
+		t.Errorf("brent: unexpected error: %v", err)
+	}
+	if math.Abs(x-math.Pi) > 1e-7 && math.Abs(x-3*math.Pi) > 1e-7 {
+		t.Errorf("brent: expected a minimum at π or 3π, got %v", x)
+	}
+}
+
+func TestMinimisationErrors(t *testing.T) {
+	// The minimum is outside of the interval.
+	f := func(x float64) float64 { return (x - 10) * (x - 10) }
+	s := Solver{}
+
+	if x, _, err := s.GoldenSection(0, 5, f); err != ErrNotBracketed || x != 5 {
+		t.Errorf("golden section: expected %v at 5, got %v at %v", ErrNotBracketed, err, x)
+	}
+	if x, _, err := s.BrentMinimum(0, 5, f); err != ErrNotBracketed || x != 5 {
+		t.Errorf("brent: expected %v at 5, got %v at %v", ErrNotBracketed, err, x)
+	}
+	limited := Solver{Tolerance: 1e-15, MaxIterations: 3}
+	if _, _, err := limited.GoldenSection(-3, 5, math.Cosh); err != ErrMaxIterations {
+		t.Errorf("golden section: expected %v, got %v", ErrMaxIterations, err)
+	}
+	if _, _, err := limited.BrentMinimum(-3, 5, math.Cosh); err != ErrMaxIterations {
+		t.Errorf("brent: expected %v, got %v", ErrMaxIterations, err)
+	}
+}
@@ -0,0 +1,72 @@
+package clustering
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/a-h/ml/internal/format"
+)
+
+// BinaryVersion is the version of the binary format written by WriteBinary.
+const BinaryVersion = 1
+
+const binaryMagic = "CLST"
+
+// ErrChecksum is returned by ReadBinary when the data doesn't match its checksum.
+var ErrChecksum = format.ErrChecksum
+
+// ErrTruncated is returned by ReadBinary when the data ends early.
+var ErrTruncated = format.ErrTruncated
+
+// Result is the result of clustering, e.g. the assignment returned by KMeans, and the centroids
+// calculated by Centroids.
+type Result struct {
+	Assignment []int
+	Centroids  []Vector
+}
+
+// WriteBinary writes the result to w in a compact binary format, of little-endian values followed
+// by a CRC32 checksum.
+func WriteBinary(w io.Writer, r Result) error {
+	fw := format.NewWriter(w, binaryMagic, BinaryVersion)
+	fw.Length(len(r.Assignment))
+	for _, a := range r.Assignment {
+		fw.Int64(int64(a))
+	}
+	fw.Length(len(r.Centroids))
+	for _, c := range r.Centroids {
+		fw.Float64s(c)
+	}
+	if err := fw.Close(); err != nil {
+		return fmt.Errorf("clustering: unable to write result: %v", err)
+	}
+	return nil
+}
+
+// ReadBinary reads a result written by WriteBinary from r. ErrChecksum is returned if the data is
+// corrupt, and ErrTruncated if it ends early. Nothing after the checksum is read from r.
+func ReadBinary(r io.Reader) (res Result, err error) {
+	fr, err := format.NewReader(r, binaryMagic, BinaryVersion)
+	if err != nil {
+		return res, readBinaryError(err)
+	}
+	count := fr.Length()
+	for i := 0; i < count && fr.Err() == nil; i++ {
+		res.Assignment = append(res.Assignment, int(fr.Int64()))
+	}
+	count = fr.Length()
+	for i := 0; i < count && fr.Err() == nil; i++ {
+		res.Centroids = append(res.Centroids, Vector(fr.Float64s()))
+	}
+	if err = fr.Close(); err != nil {
+		return Result{}, readBinaryError(err)
+	}
+	return
+}
+
+func readBinaryError(err error) error {
+	if err == ErrChecksum || err == ErrTruncated {
+		return err
+	}
+	return fmt.Errorf("clustering: unable to read result: %v", err)
+}
@@ -0,0 +1,43 @@
+package clustering
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestResultBinary(t *testing.T) {
+	expected := Result{
+		Assignment: []int{0, 1, 1, 0, 2},
+		Centroids: []Vector{
+			{1.0 / 3.0, -2},
+			{0.1, 0.2},
+			{1e-300, 1e300},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, expected); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	data := append([]byte(nil), buf.Bytes()...)
+	actual, err := ReadBinary(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-6] ^= 0x01
+	if _, err := ReadBinary(bytes.NewReader(corrupt)); err != ErrChecksum {
+		t.Errorf("corrupt: expected ErrChecksum, got %v", err)
+	}
+	if _, err := ReadBinary(bytes.NewReader(data[:len(data)-2])); err != ErrTruncated {
+		t.Errorf("truncated: expected ErrTruncated, got %v", err)
+	}
+	if _, err := ReadBinary(bytes.NewReader([]byte("RBFN\x01\x00"))); err == nil {
+		t.Errorf("wrong magic number: expected an error, got nil")
+	}
+}
@@ -59,19 +59,25 @@ func Centroids(data []Vector, n int, assignments []int, centroids *[]Vector) (er
 	}
 
 	// Make each centroid index be the sum of data in that cluster.
+	counts := make([]int, n)
 	for i, v := range data {
 		assignment := assignments[i]
+		counts[assignment]++
 		for j, vj := range v {
 			cs[assignment][j] += vj
 		}
 	}
 
-	// Divide by the length of data to get the average.
-	for _, c := range cs {
+	// Divide by the amount of data in each cluster to get the average.
+	for ci, c := range cs {
+		if counts[ci] == 0 {
+			continue
+		}
 		for i, f := range c {
-			c[i] = f / float64(len(data))
+			c[i] = f / float64(counts[ci])
 		}
 	}
 
+	*centroids = cs
 	return
 }
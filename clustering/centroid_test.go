@@ -59,3 +59,27 @@ func TestCentroid(t *testing.T) {
 		}
 	}
 }
+
+func TestCentroids(t *testing.T) {
+	data := []Vector{
+		{1, 1},
+		{3, 3},
+		{10, 20},
+		{-1, 0},
+		{1, 0},
+		{0, 3},
+	}
+	assignments := []int{0, 0, 1, 2, 2, 2}
+	var centroids []Vector
+	if err := Centroids(data, 3, assignments, &centroids); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Vector{
+		{2, 2},
+		{10, 20},
+		{0, 1},
+	}
+	if !Clusters([]Cluster{centroids}).Eq(Clusters([]Cluster{expected})) {
+		t.Errorf("expected %v, got %v", expected, centroids)
+	}
+}
@@ -0,0 +1,27 @@
+package dual
+
+// Derivative returns the value of f at point x, and its exact derivative.
+func Derivative(x float64, f func(x Number) Number) (y, dy float64) {
+	r := f(Variable(x))
+	return r.Value, r.Derivative
+}
+
+// Gradient returns the value of f at point x, and its exact partial derivatives. f is calculated
+// once for each dimension of x.
+func Gradient(x []float64, f func(x []Number) Number) (y float64, g []float64) {
+	g = make([]float64, len(x))
+	xd := make([]Number, len(x))
+	for i, xi := range x {
+		xd[i] = Constant(xi)
+	}
+	if len(x) == 0 {
+		return f(xd).Value, g
+	}
+	for i := range x {
+		xd[i].Derivative = 1
+		r := f(xd)
+		xd[i].Derivative = 0
+		y, g[i] = r.Value, r.Derivative
+	}
+	return
+}
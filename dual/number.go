@@ -0,0 +1,140 @@
+package dual
+
+import "math"
+
+// Number is a dual number, a + bε where ε² = 0. When a function is calculated using dual numbers,
+// the Derivative of the result is the exact derivative of the function, so long as the input
+// variable had a Derivative of 1.
+type Number struct {
+	Value      float64
+	Derivative float64
+}
+
+// Variable returns a dual number for the variable being differentiated against.
+func Variable(x float64) Number {
+	return Number{Value: x, Derivative: 1}
+}
+
+// Constant returns a dual number which does not vary with the variable being differentiated.
+func Constant(x float64) Number {
+	return Number{Value: x}
+}
+
+// Add returns a + b.
+func (a Number) Add(b Number) Number {
+	return Number{
+		Value:      a.Value + b.Value,
+		Derivative: a.Derivative + b.Derivative,
+	}
+}
+
+// Sub returns a - b.
+func (a Number) Sub(b Number) Number {
+	return Number{
+		Value:      a.Value - b.Value,
+		Derivative: a.Derivative - b.Derivative,
+	}
+}
+
+// Mul returns a * b.
+func (a Number) Mul(b Number) Number {
+	return Number{
+		Value:      a.Value * b.Value,
+		Derivative: a.Derivative*b.Value + a.Value*b.Derivative,
+	}
+}
+
+// Div returns a / b.
+func (a Number) Div(b Number) Number {
+	return Number{
+		Value:      a.Value / b.Value,
+		Derivative: (a.Derivative*b.Value - a.Value*b.Derivative) / (b.Value * b.Value),
+	}
+}
+
+// Neg returns -a.
+func (a Number) Neg() Number {
+	return Number{
+		Value:      -a.Value,
+		Derivative: -a.Derivative,
+	}
+}
+
+// Scale returns a * s.
+func (a Number) Scale(s float64) Number {
+	return Number{
+		Value:      a.Value * s,
+		Derivative: a.Derivative * s,
+	}
+}
+
+// Exp returns e**a.
+func Exp(a Number) Number {
+	v := math.Exp(a.Value)
+	return Number{
+		Value:      v,
+		Derivative: v * a.Derivative,
+	}
+}
+
+// Log returns the natural logarithm of a.
+func Log(a Number) Number {
+	return Number{
+		Value:      math.Log(a.Value),
+		Derivative: a.Derivative / a.Value,
+	}
+}
+
+// Sin returns the sine of a.
+func Sin(a Number) Number {
+	return Number{
+		Value:      math.Sin(a.Value),
+		Derivative: math.Cos(a.Value) * a.Derivative,
+	}
+}
+
+// Cos returns the cosine of a.
+func Cos(a Number) Number {
+	return Number{
+		Value:      math.Cos(a.Value),
+		Derivative: -math.Sin(a.Value) * a.Derivative,
+	}
+}
+
+// Tanh returns the hyperbolic tangent of a.
+func Tanh(a Number) Number {
+	v := math.Tanh(a.Value)
+	return Number{
+		Value:      v,
+		Derivative: (1 - v*v) * a.Derivative,
+	}
+}
+
+// Sqrt returns the square root of a.
+func Sqrt(a Number) Number {
+	v := math.Sqrt(a.Value)
+	return Number{
+		Value:      v,
+		Derivative: a.Derivative / (2 * v),
+	}
+}
+
+// Pow returns a**p.
+func Pow(a Number, p float64) Number {
+	if p == 0 {
+		return Constant(1)
+	}
+	return Number{
+		Value:      math.Pow(a.Value, p),
+		Derivative: p * math.Pow(a.Value, p-1) * a.Derivative,
+	}
+}
+
+// PowNumber returns a**b, where both a and b may vary. a must be positive.
+func PowNumber(a, b Number) Number {
+	v := math.Pow(a.Value, b.Value)
+	return Number{
+		Value:      v,
+		Derivative: v * (b.Derivative*math.Log(a.Value) + b.Value*a.Derivative/a.Value),
+	}
+}
@@ -0,0 +1,135 @@
+package dual
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFunctions(t *testing.T) {
+	tests := []struct {
+		name       string
+		f          func(x Number) Number
+		x          float64
+		expected   float64
+		derivative float64
+	}{
+		{
+			name:       "add and multiply",
+			f:          func(x Number) Number { return x.Mul(x).Add(x.Scale(3)).Sub(Constant(1)) },
+			x:          2,
+			expected:   9,
+			derivative: 7,
+		},
+		{
+			name:       "divide",
+			f:          func(x Number) Number { return Constant(1).Div(x) },
+			x:          2,
+			expected:   0.5,
+			derivative: -0.25,
+		},
+		{
+			name:       "negate",
+			f:          func(x Number) Number { return x.Neg() },
+			x:          2,
+			expected:   -2,
+			derivative: -1,
+		},
+		{
+			name:       "exp",
+			f:          Exp,
+			x:          1,
+			expected:   math.E,
+			derivative: math.E,
+		},
+		{
+			name:       "log",
+			f:          Log,
+			x:          2,
+			expected:   math.Log(2),
+			derivative: 0.5,
+		},
+		{
+			name:       "sin",
+			f:          Sin,
+			x:          1,
+			expected:   math.Sin(1),
+			derivative: math.Cos(1),
+		},
+		{
+			name:       "cos",
+			f:          Cos,
+			x:          1,
+			expected:   math.Cos(1),
+			derivative: -math.Sin(1),
+		},
+		{
+			name:       "tanh",
+			f:          Tanh,
+			x:          0,
+			expected:   0,
+			derivative: 1,
+		},
+		{
+			name:       "sqrt",
+			f:          Sqrt,
+			x:          4,
+			expected:   2,
+			derivative: 0.25,
+		},
+		{
+			name:       "pow",
+			f:          func(x Number) Number { return Pow(x, 3) },
+			x:          2,
+			expected:   8,
+			derivative: 12,
+		},
+		{
+			name:       "pow of zero",
+			f:          func(x Number) Number { return Pow(x, 0) },
+			x:          0,
+			expected:   1,
+			derivative: 0,
+		},
+		{
+			name:       "x to the power of x",
+			f:          func(x Number) Number { return PowNumber(x, x) },
+			x:          2,
+			expected:   4,
+			derivative: 4 * (math.Log(2) + 1),
+		},
+		{
+			name:       "chain rule",
+			f:          func(x Number) Number { return Exp(Sin(x.Mul(x))) },
+			x:          0.5,
+			expected:   math.Exp(math.Sin(0.25)),
+			derivative: math.Exp(math.Sin(0.25)) * math.Cos(0.25) * 2 * 0.5,
+		},
+	}
+
+	for _, test := range tests {
+		y, dy := Derivative(test.x, test.f)
+		if math.Abs(y-test.expected) > 1e-12 {
+			t.Errorf("%s: expected value %v, got %v", test.name, test.expected, y)
+		}
+		if math.Abs(dy-test.derivative) > 1e-12 {
+			t.Errorf("%s: expected derivative %v, got %v", test.name, test.derivative, dy)
+		}
+	}
+}
+
+func TestGradient(t *testing.T) {
+	f := func(x []Number) Number {
+		return x[0].Mul(x[1]).Add(Sin(x[2]))
+	}
+	y, g := Gradient([]float64{2, 3, 0}, f)
+	if y != 6 {
+		t.Errorf("expected value 6, got %v", y)
+	}
+	expected := []float64{3, 2, 1}
+	for i := range expected {
+		if g[i] != expected[i] {
+			t.Errorf("expected gradient %v, got %v", expected, g)
+			break
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// Package format reads and writes the binary model formats used by the rbf and clustering
+// packages.
+//
+// Each file starts with a 4 byte magic number and a little-endian uint16 version, followed by the
+// model's values, and ends with the CRC32 (IEEE) checksum of everything before it. Integers and
+// float64 values are little-endian, slices and strings are prefixed with their uint32 length.
+package format
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// ErrChecksum is returned when the data doesn't match its checksum.
+var ErrChecksum = errors.New("format: checksum mismatch, the data is corrupt")
+
+// ErrTruncated is returned when the data ends before the checksum.
+var ErrTruncated = errors.New("format: unexpected end of data, the data is truncated")
+
+// chunk limits how much is allocated ahead of reading a slice, so that a corrupt length can't
+// allocate more memory than the data actually contains.
+const chunk = 1 << 16
+
+// Writer writes values and keeps a running checksum. After the first error, all writes are
+// ignored and the error is returned by Close.
+type Writer struct {
+	bw  *bufio.Writer
+	w   io.Writer
+	crc hash.Hash32
+	buf [8]byte
+	err error
+}
+
+// NewWriter writes the magic number and version to w, and returns a Writer for the model's values.
+// Writes to w are buffered, and flushed by Close.
+func NewWriter(w io.Writer, magic string, version uint16) *Writer {
+	fw := &Writer{bw: bufio.NewWriter(w), crc: crc32.NewIEEE()}
+	fw.w = io.MultiWriter(fw.bw, fw.crc)
+	fw.write([]byte(magic))
+	fw.Uint16(version)
+	return fw
+}
+
+func (w *Writer) write(b []byte) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = w.w.Write(b)
+}
+
+// Uint8 writes v.
+func (w *Writer) Uint8(v uint8) {
+	w.buf[0] = v
+	w.write(w.buf[:1])
+}
+
+// Uint16 writes v.
+func (w *Writer) Uint16(v uint16) {
+	binary.LittleEndian.PutUint16(w.buf[:2], v)
+	w.write(w.buf[:2])
+}
+
+// Uint32 writes v.
+func (w *Writer) Uint32(v uint32) {
+	binary.LittleEndian.PutUint32(w.buf[:4], v)
+	w.write(w.buf[:4])
+}
+
+// Int64 writes v.
+func (w *Writer) Int64(v int64) {
+	binary.LittleEndian.PutUint64(w.buf[:], uint64(v))
+	w.write(w.buf[:])
+}
+
+// Float64 writes v.
+func (w *Writer) Float64(v float64) {
+	binary.LittleEndian.PutUint64(w.buf[:], math.Float64bits(v))
+	w.write(w.buf[:])
+}
+
+// Length writes the length of a slice or string.
+func (w *Writer) Length(n int) {
+	if w.err == nil && uint64(n) > math.MaxUint32 {
+		w.err = fmt.Errorf("format: length %d is too large", n)
+	}
+	w.Uint32(uint32(n))
+}
+
+// Float64s writes the length of v, followed by its values.
+func (w *Writer) Float64s(v []float64) {
+	w.Length(len(v))
+	for _, f := range v {
+		w.Float64(f)
+	}
+}
+
+// Bytes writes the length of v, followed by v.
+func (w *Writer) Bytes(v []byte) {
+	w.Length(len(v))
+	w.write(v)
+}
+
+// String writes the length of v, followed by v.
+func (w *Writer) String(v string) {
+	w.Bytes([]byte(v))
+}
+
+// Close writes the checksum, flushes the buffer, and returns the first error encountered while
+// writing. It doesn't close the underlying io.Writer.
+func (w *Writer) Close() error {
+	binary.LittleEndian.PutUint32(w.buf[:4], w.crc.Sum32())
+	w.write(w.buf[:4])
+	if w.err == nil {
+		w.err = w.bw.Flush()
+	}
+	return w.err
+}
+
+// Reader reads values and keeps a running checksum. After the first error, reads return zero
+// values and the error is returned by Close.
+type Reader struct {
+	src io.Reader
+	r   io.Reader
+	crc hash.Hash32
+	buf [8]byte
+	err error
+	// Version of the data.
+	Version uint16
+}
+
+// NewReader reads and checks the magic number, and reads the version. The Reader never reads past
+// the checksum, so that other data can follow it in r. Slices are read in large blocks, but other
+// values are read individually, so files should be wrapped in a bufio.Reader if nothing follows
+// the checksum.
+func NewReader(r io.Reader, magic string, version uint16) (*Reader, error) {
+	fr := &Reader{src: r, crc: crc32.NewIEEE()}
+	fr.r = io.TeeReader(r, fr.crc)
+	m := make([]byte, len(magic))
+	fr.read(m)
+	fr.Version = fr.Uint16()
+	if fr.err != nil {
+		return nil, fr.err
+	}
+	if string(m) != magic {
+		return nil, fmt.Errorf("format: expected magic number %q, got %q", magic, m)
+	}
+	if fr.Version < 1 || fr.Version > version {
+		return nil, fmt.Errorf("format: unsupported version %d, expected a version between 1 and %d",
+			fr.Version, version)
+	}
+	return fr, nil
+}
+
+func (r *Reader) read(b []byte) {
+	if r.err != nil {
+		for i := range b {
+			b[i] = 0
+		}
+		return
+	}
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.fail(err)
+	}
+}
+
+func (r *Reader) fail(err error) {
+	if r.err != nil {
+		return
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = ErrTruncated
+	}
+	r.err = err
+}
+
+// Err returns the first error encountered while reading.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Fail stops reading, so that Close returns err.
+func (r *Reader) Fail(err error) {
+	r.fail(err)
+}
+
+// Uint8 reads a uint8.
+func (r *Reader) Uint8() uint8 {
+	r.read(r.buf[:1])
+	return r.buf[0]
+}
+
+// Uint16 reads a uint16.
+func (r *Reader) Uint16() uint16 {
+	r.read(r.buf[:2])
+	return binary.LittleEndian.Uint16(r.buf[:2])
+}
+
+// Uint32 reads a uint32.
+func (r *Reader) Uint32() uint32 {
+	r.read(r.buf[:4])
+	return binary.LittleEndian.Uint32(r.buf[:4])
+}
+
+// Int64 reads an int64.
+func (r *Reader) Int64() int64 {
+	r.read(r.buf[:])
+	return int64(binary.LittleEndian.Uint64(r.buf[:]))
+}
+
+// Float64 reads a float64.
+func (r *Reader) Float64() float64 {
+	r.read(r.buf[:])
+	return math.Float64frombits(binary.LittleEndian.Uint64(r.buf[:]))
+}
+
+// Length reads the length of a slice or string.
+func (r *Reader) Length() int {
+	return int(r.Uint32())
+}
+
+// Float64s reads a slice written by Writer.Float64s. An empty slice is returned as nil.
+func (r *Reader) Float64s() (v []float64) {
+	n := r.Length()
+	var b []byte
+	for len(v) < n && r.err == nil {
+		count := minInt(n-len(v), chunk)
+		if len(b) < count*8 {
+			b = make([]byte, count*8)
+		}
+		r.read(b[:count*8])
+		for i := 0; i < count; i++ {
+			v = append(v, math.Float64frombits(binary.LittleEndian.Uint64(b[i*8:])))
+		}
+	}
+	if r.err != nil {
+		return nil
+	}
+	return
+}
+
+// Bytes reads a slice written by Writer.Bytes.
+func (r *Reader) Bytes() (v []byte) {
+	n := r.Length()
+	for len(v) < n && r.err == nil {
+		b := make([]byte, minInt(n-len(v), chunk))
+		r.read(b)
+		v = append(v, b...)
+	}
+	if r.err != nil {
+		return nil
+	}
+	return
+}
+
+// String reads a string written by Writer.String.
+func (r *Reader) String() string {
+	return string(r.Bytes())
+}
+
+// Close reads and checks the checksum, and returns the first error encountered while reading.
+// It doesn't close the underlying io.Reader.
+func (r *Reader) Close() error {
+	if r.err != nil {
+		return r.err
+	}
+	expected := r.crc.Sum32()
+	// Read the checksum directly, since it isn't part of the checksum.
+	if _, err := io.ReadFull(r.src, r.buf[:4]); err != nil {
+		r.fail(err)
+		return r.err
+	}
+	if binary.LittleEndian.Uint32(r.buf[:4]) != expected {
+		r.err = ErrChecksum
+	}
+	return r.err
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,196 @@
+package format
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "TEST", 2)
+	w.Uint8(7)
+	w.Uint16(300)
+	w.Uint32(70000)
+	w.Int64(-5)
+	w.Float64(math.Pi)
+	w.Float64s([]float64{1, math.Inf(1), -0.5})
+	w.Float64s(nil)
+	w.String("gaussian")
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+
+	r, err := NewReader(&buf, "TEST", 2)
+	if err != nil {
+		t.Fatalf("unexpected error creating reader: %v", err)
+	}
+	if r.Version != 2 {
+		t.Errorf("expected version 2, got %d", r.Version)
+	}
+	if v := r.Uint8(); v != 7 {
+		t.Errorf("uint8: expected 7, got %v", v)
+	}
+	if v := r.Uint16(); v != 300 {
+		t.Errorf("uint16: expected 300, got %v", v)
+	}
+	if v := r.Uint32(); v != 70000 {
+		t.Errorf("uint32: expected 70000, got %v", v)
+	}
+	if v := r.Int64(); v != -5 {
+		t.Errorf("int64: expected -5, got %v", v)
+	}
+	if v := r.Float64(); v != math.Pi {
+		t.Errorf("float64: expected %v, got %v", math.Pi, v)
+	}
+	if v := r.Float64s(); !reflect.DeepEqual(v, []float64{1, math.Inf(1), -0.5}) {
+		t.Errorf("float64s: expected [1 +Inf -0.5], got %v", v)
+	}
+	if v := r.Float64s(); v != nil {
+		t.Errorf("empty float64s: expected nil, got %v", v)
+	}
+	if v := r.String(); v != "gaussian" {
+		t.Errorf("string: expected gaussian, got %q", v)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("unexpected error closing: %v", err)
+	}
+}
+
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestWriterIsBuffered(t *testing.T) {
+	var buf countingWriter
+	w := NewWriter(&buf, "TEST", 1)
+	w.Float64s(make([]float64, 10000))
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	// 80KB of values should be written in 4KB blocks, rather than one write per value.
+	if buf.writes > 25 {
+		t.Errorf("expected at most 25 writes, got %d", buf.writes)
+	}
+	cr := &countingReader{r: &buf.Buffer}
+	r, err := NewReader(cr, "TEST", 1)
+	if err != nil {
+		t.Fatalf("unexpected error creating reader: %v", err)
+	}
+	if v := r.Float64s(); len(v) != 10000 {
+		t.Errorf("expected 10000 values, got %d", len(v))
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("unexpected error closing: %v", err)
+	}
+	// The magic number, version, length, values and checksum.
+	if cr.reads > 5 {
+		t.Errorf("expected at most 5 reads, got %d", cr.reads)
+	}
+}
+
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.r.Read(p)
+}
+
+func TestReaderDoesNotReadPastTheChecksum(t *testing.T) {
+	var buf bytes.Buffer
+	for _, v := range []float64{1, 2} {
+		w := NewWriter(&buf, "TEST", 1)
+		w.Float64s([]float64{v, v})
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	buf.WriteString("end")
+
+	for _, v := range []float64{1, 2} {
+		r, err := NewReader(&buf, "TEST", 1)
+		if err != nil {
+			t.Fatalf("unexpected error creating reader: %v", err)
+		}
+		if actual := r.Float64s(); !reflect.DeepEqual(actual, []float64{v, v}) {
+			t.Errorf("expected [%v %v], got %v", v, v, actual)
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("unexpected error closing: %v", err)
+		}
+	}
+	if buf.String() != "end" {
+		t.Errorf("expected the data after the checksums to remain, got %q", buf.String())
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "TEST", 2)
+	w.Float64s([]float64{1, 2, 3})
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	data := buf.Bytes()
+
+	tests := []struct {
+		name     string
+		data     []byte
+		version  uint16
+		expected error
+	}{
+		{
+			name:     "truncated header",
+			data:     data[:3],
+			version:  2,
+			expected: ErrTruncated,
+		},
+		{
+			name:     "truncated values",
+			data:     data[:len(data)-9],
+			version:  2,
+			expected: ErrTruncated,
+		},
+		{
+			name:     "truncated checksum",
+			data:     data[:len(data)-1],
+			version:  2,
+			expected: ErrTruncated,
+		},
+		{
+			name:     "corrupt values",
+			data:     append(append([]byte(nil), data[:len(data)-5]...), 0xFF, 0, 0, 0, 0),
+			version:  2,
+			expected: ErrChecksum,
+		},
+	}
+
+	for _, test := range tests {
+		r, err := NewReader(bytes.NewReader(test.data), "TEST", test.version)
+		if err == nil {
+			r.Float64s()
+			err = r.Close()
+		}
+		if err != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+	}
+
+	if _, err := NewReader(bytes.NewReader(data), "TEST", 1); err == nil {
+		t.Errorf("future version: expected an error, got nil")
+	}
+	if _, err := NewReader(bytes.NewReader(data), "BEST", 2); err == nil {
+		t.Errorf("wrong magic number: expected an error, got nil")
+	}
+}
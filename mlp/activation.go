@@ -0,0 +1,103 @@
+package mlp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Activation is the function applied to the output of each neuron in a Layer. The zero value is
+// ActivationLinear.
+type Activation string
+
+const (
+	// ActivationLinear leaves the output unchanged.
+	ActivationLinear Activation = "linear"
+	// ActivationSigmoid is 1 / (1 + exp(-x)).
+	ActivationSigmoid Activation = "sigmoid"
+	// ActivationTanh is tanh(x).
+	ActivationTanh Activation = "tanh"
+	// ActivationReLU is max(0, x).
+	ActivationReLU Activation = "relu"
+	// ActivationSoftmax is exp(xᵢ) / Σexp(xⱼ), which turns the layer's outputs into probabilities.
+	ActivationSoftmax Activation = "softmax"
+)
+
+func (a Activation) name() string {
+	if a == "" {
+		return string(ActivationLinear)
+	}
+	return string(a)
+}
+
+// apply the activation to z, writing the result to op.
+func (a Activation) apply(z, op []float64) error {
+	switch a {
+	case "", ActivationLinear:
+		copy(op, z)
+	case ActivationSigmoid:
+		for i, v := range z {
+			op[i] = 1 / (1 + math.Exp(-v))
+		}
+	case ActivationTanh:
+		for i, v := range z {
+			op[i] = math.Tanh(v)
+		}
+	case ActivationReLU:
+		for i, v := range z {
+			op[i] = math.Max(0, v)
+		}
+	case ActivationSoftmax:
+		// Subtract the maximum to avoid overflow.
+		largest := math.Inf(-1)
+		for _, v := range z {
+			largest = math.Max(largest, v)
+		}
+		var sum float64
+		for i, v := range z {
+			op[i] = math.Exp(v - largest)
+			sum += op[i]
+		}
+		for i := range op {
+			op[i] /= sum
+		}
+	default:
+		return fmt.Errorf("mlp: unknown activation %q", string(a))
+	}
+	return nil
+}
+
+// gradient converts the gradient of the error with respect to the activation's output a into the
+// gradient with respect to its input z, writing the result to dz.
+func (a Activation) gradient(z, op, da, dz []float64) error {
+	switch a {
+	case "", ActivationLinear:
+		copy(dz, da)
+	case ActivationSigmoid:
+		for i, v := range op {
+			dz[i] = da[i] * v * (1 - v)
+		}
+	case ActivationTanh:
+		for i, v := range op {
+			dz[i] = da[i] * (1 - v*v)
+		}
+	case ActivationReLU:
+		for i, v := range z {
+			if v > 0 {
+				dz[i] = da[i]
+			} else {
+				dz[i] = 0
+			}
+		}
+	case ActivationSoftmax:
+		var dot float64
+		for i, v := range op {
+			dot += da[i] * v
+		}
+		for i, v := range op {
+			dz[i] = v * (da[i] - dot)
+		}
+	default:
+		return fmt.Errorf("mlp: unknown activation %q", string(a))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package mlp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestActivations(t *testing.T) {
+	tests := []struct {
+		name       string
+		activation Activation
+		input      []float64
+		expected   []float64
+	}{
+		{
+			name:     "zero value is linear",
+			input:    []float64{-1, 0, 2},
+			expected: []float64{-1, 0, 2},
+		},
+		{
+			name:       "sigmoid",
+			activation: ActivationSigmoid,
+			input:      []float64{-1, 0, 2},
+			expected:   []float64{1 / (1 + math.E), 0.5, 1 / (1 + math.Exp(-2))},
+		},
+		{
+			name:       "tanh",
+			activation: ActivationTanh,
+			input:      []float64{-1, 0, 2},
+			expected:   []float64{math.Tanh(-1), 0, math.Tanh(2)},
+		},
+		{
+			name:       "relu",
+			activation: ActivationReLU,
+			input:      []float64{-1, 0, 2},
+			expected:   []float64{0, 0, 2},
+		},
+		{
+			name:       "softmax",
+			activation: ActivationSoftmax,
+			input:      []float64{0, math.Log(2), math.Log(5)},
+			expected:   []float64{0.125, 0.25, 0.625},
+		},
+		{
+			name:       "softmax of large values",
+			activation: ActivationSoftmax,
+			input:      []float64{1000, 1000},
+			expected:   []float64{0.5, 0.5},
+		},
+	}
+
+	for _, test := range tests {
+		actual := make([]float64, len(test.input))
+		if err := test.activation.apply(test.input, actual); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		for i := range test.expected {
+			if math.Abs(actual[i]-test.expected[i]) > 1e-12 {
+				t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestActivationGradients(t *testing.T) {
+	activations := []Activation{ActivationLinear, ActivationSigmoid, ActivationTanh, ActivationReLU, ActivationSoftmax}
+	z := []float64{-0.5, 0.25, 1.5}
+	da := []float64{1, -2, 0.5}
+	// The error is Σ daᵢ aᵢ, so its gradient with respect to z can be compared to finite differences.
+	e := func(a Activation, z []float64) float64 {
+		op := make([]float64, len(z))
+		a.apply(z, op)
+		var sum float64
+		for i, v := range op {
+			sum += da[i] * v
+		}
+		return sum
+	}
+
+	for _, a := range activations {
+		op := make([]float64, len(z))
+		if err := a.apply(z, op); err != nil {
+			t.Fatalf("%s: unexpected error: %v", a.name(), err)
+		}
+		actual := make([]float64, len(z))
+		if err := a.gradient(z, op, da, actual); err != nil {
+			t.Fatalf("%s: unexpected error: %v", a.name(), err)
+		}
+		for i := range z {
+			h := 1e-6
+			up := append([]float64(nil), z...)
+			up[i] += h
+			down := append([]float64(nil), z...)
+			down[i] -= h
+			expected := (e(a, up) - e(a, down)) / (2 * h)
+			if math.Abs(actual[i]-expected) > 1e-6 {
+				t.Errorf("%s: expected gradient %v at %d, got %v", a.name(), expected, i, actual[i])
+			}
+		}
+	}
+}
+
+func TestUnknownActivation(t *testing.T) {
+	a := Activation("step")
+	op := make([]float64, 1)
+	if err := a.apply([]float64{1}, op); err == nil {
+		t.Errorf("expected an error applying an unknown activation, got nil")
+	}
+	if err := a.gradient([]float64{1}, op, []float64{1}, op); err == nil {
+		t.Errorf("expected an error calculating the gradient of an unknown activation, got nil")
+	}
+}
@@ -0,0 +1,126 @@
+package mlp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/a-h/ml/random"
+)
+
+// Initialisation is the scheme used to choose a new Layer's starting weights.
+type Initialisation string
+
+const (
+	// InitialisationUniform chooses weights uniformly between -1 and 1.
+	InitialisationUniform Initialisation = "uniform"
+	// InitialisationXavier chooses weights uniformly between ±√(6 / (inputs + outputs)), which
+	// suits sigmoid and tanh activations.
+	InitialisationXavier Initialisation = "xavier"
+	// InitialisationHe chooses weights uniformly between ±√(6 / inputs), which suits ReLU
+	// activations.
+	InitialisationHe Initialisation = "he"
+)
+
+// NewLayer creates a layer of neurons, with weights chosen using the initialisation scheme and
+// biases of zero.
+func NewLayer(inputs, outputs int, a Activation, init Initialisation) (l *Layer, err error) {
+	if inputs <= 0 || outputs <= 0 {
+		err = fmt.Errorf("mlp: a layer must have at least one input and output, but got %d inputs and %d outputs",
+			inputs, outputs)
+		return
+	}
+	var limit float64
+	switch init {
+	case InitialisationUniform:
+		limit = 1
+	case InitialisationXavier:
+		limit = math.Sqrt(6 / float64(inputs+outputs))
+	case InitialisationHe:
+		limit = math.Sqrt(6 / float64(inputs))
+	default:
+		err = fmt.Errorf("mlp: unknown initialisation %q", string(init))
+		return
+	}
+	l = &Layer{
+		Inputs:     inputs,
+		Weights:    random.Float64Vector(-limit, limit, inputs*outputs),
+		Biases:     make([]float64, outputs),
+		Activation: a,
+	}
+	return
+}
+
+// Layer is a fully connected layer of neurons.
+type Layer struct {
+	// Inputs is the number of inputs to the layer.
+	Inputs int
+	// Weights of each input to each neuron. The weights of neuron i are
+	// Weights[i*Inputs : (i+1)*Inputs].
+	Weights []float64
+	// Biases of each neuron.
+	Biases []float64
+	// Activation applied to the output of each neuron.
+	Activation Activation `json:",omitempty"`
+}
+
+// OutputCount returns the number of neurons in the layer.
+func (l *Layer) OutputCount() int {
+	return len(l.Biases)
+}
+
+// Calculate the output of the layer.
+func (l *Layer) Calculate(input []float64) (op []float64, err error) {
+	z, err := l.weightedSum(input)
+	if err != nil {
+		return
+	}
+	op = make([]float64, len(z))
+	err = l.Activation.apply(z, op)
+	return
+}
+
+// weightedSum returns the output of each neuron before the activation is applied.
+func (l *Layer) weightedSum(input []float64) (z []float64, err error) {
+	if len(input) != l.Inputs {
+		err = fmt.Errorf("mlp: the layer has %d inputs, but the input vector has a length of %d values",
+			l.Inputs, len(input))
+		return
+	}
+	if len(l.Weights) != l.Inputs*len(l.Biases) {
+		err = fmt.Errorf("mlp: a layer with %d inputs and %d outputs should have %d weights, but has %d",
+			l.Inputs, len(l.Biases), l.Inputs*len(l.Biases), len(l.Weights))
+		return
+	}
+	z = make([]float64, len(l.Biases))
+	for i, b := range l.Biases {
+		z[i] = b
+		for j, x := range input {
+			z[i] += l.Weights[i*l.Inputs+j] * x
+		}
+	}
+	return
+}
+
+// GetMemorySize returns the number of weights and biases in the layer.
+func (l *Layer) GetMemorySize() int {
+	return len(l.Weights) + len(l.Biases)
+}
+
+// GetMemory returns the layer's weights, followed by its biases.
+func (l *Layer) GetMemory() (op []float64) {
+	op = append(op, l.Weights...)
+	op = append(op, l.Biases...)
+	return
+}
+
+// SetMemory updates the layer's weights and biases. An error is returned if m isn't the same
+// length as the layer's memory.
+func (l *Layer) SetMemory(m []float64) error {
+	if len(m) != l.GetMemorySize() {
+		return fmt.Errorf("mlp: the layer has a memory size of %d, but %d values were provided",
+			l.GetMemorySize(), len(m))
+	}
+	l.Weights = m[:len(l.Weights)]
+	l.Biases = m[len(l.Weights):]
+	return nil
+}
@@ -0,0 +1,72 @@
+package mlp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLayer(t *testing.T) {
+	l := &Layer{
+		Inputs:     2,
+		Weights:    []float64{1, 2, -1, 0.5},
+		Biases:     []float64{0.5, -1},
+		Activation: ActivationReLU,
+	}
+	actual, err := l.Calculate([]float64{1, -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 1 - 2 + 0.5 = -0.5, -1 - 0.5 - 1 = -2.5, so only the bias of the first neuron passes.
+	expected := []float64{0, 0}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	actual, err = l.Calculate([]float64{2, 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []float64{4.5, 0}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if _, err := l.Calculate([]float64{1}); err == nil {
+		t.Errorf("expected an error for a short input, got nil")
+	}
+}
+
+func TestNewLayer(t *testing.T) {
+	tests := []struct {
+		name  string
+		init  Initialisation
+		limit float64
+	}{
+		{name: "uniform", init: InitialisationUniform, limit: 1},
+		{name: "xavier", init: InitialisationXavier, limit: math.Sqrt(6.0 / 50.0)},
+		{name: "he", init: InitialisationHe, limit: math.Sqrt(6.0 / 40.0)},
+	}
+
+	for _, test := range tests {
+		l, err := NewLayer(40, 10, ActivationTanh, test.init)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(l.Weights) != 400 || len(l.Biases) != 10 || l.GetMemorySize() != 410 {
+			t.Errorf("%s: expected 400 weights and 10 biases, got %d and %d", test.name, len(l.Weights), len(l.Biases))
+		}
+		for _, w := range l.Weights {
+			if math.Abs(w) > test.limit {
+				t.Errorf("%s: expected weights between ±%v, got %v", test.name, test.limit, w)
+				break
+			}
+		}
+	}
+
+	if _, err := NewLayer(2, 2, ActivationTanh, Initialisation("zeros")); err == nil {
+		t.Errorf("expected an error for an unknown initialisation, got nil")
+	}
+	if _, err := NewLayer(0, 2, ActivationTanh, InitialisationUniform); err == nil {
+		t.Errorf("expected an error for a layer without inputs, got nil")
+	}
+}
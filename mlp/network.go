@@ -0,0 +1,180 @@
+package mlp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// New creates a network with layers of the given sizes, where sizes[0] is the number of inputs
+// and each following value is the number of neurons in the next layer. activations are applied
+// to each layer in turn. If fewer activations than layers are provided, the final activation is
+// used for the remaining layers.
+func New(init Initialisation, sizes []int, activations ...Activation) (n Network, err error) {
+	if len(sizes) < 2 {
+		err = errors.New("mlp: at least two sizes are required, the number of inputs and outputs")
+		return
+	}
+	if len(activations) == 0 {
+		activations = []Activation{ActivationLinear}
+	}
+	layers := make([]*Layer, len(sizes)-1)
+	for i := range layers {
+		a := activations[len(activations)-1]
+		if i < len(activations) {
+			a = activations[i]
+		}
+		layers[i], err = NewLayer(sizes[i], sizes[i+1], a, init)
+		if err != nil {
+			return
+		}
+	}
+	return NewNetwork(layers...)
+}
+
+// NewNetwork creates a network from the layers, checking that the number of outputs of each layer
+// matches the number of inputs of the next.
+func NewNetwork(layers ...*Layer) (n Network, err error) {
+	if len(layers) == 0 {
+		err = errors.New("mlp: unable to create network, since there are no layers")
+		return
+	}
+	for i := 1; i < len(layers); i++ {
+		if layers[i].Inputs != layers[i-1].OutputCount() {
+			err = fmt.Errorf("mlp: layer %d has %d inputs, but layer %d has %d outputs",
+				i, layers[i].Inputs, i-1, layers[i-1].OutputCount())
+			return
+		}
+	}
+	n = Network(layers)
+	return
+}
+
+// Network is a feed-forward multi-layer perceptron. The output of each layer is the input to the
+// next.
+type Network []*Layer
+
+func (n Network) String() string {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Sprintf("mlp.Network: error marshalling to JSON: %v", err)
+	}
+	return string(b)
+}
+
+// OutputCount returns the number of outputs of the network.
+func (n Network) OutputCount() int {
+	if len(n) == 0 {
+		return 0
+	}
+	return n[len(n)-1].OutputCount()
+}
+
+// Calculate the output of the network.
+func (n Network) Calculate(input []float64) (op []float64, err error) {
+	if len(n) == 0 {
+		err = errors.New("mlp: unable to calculate result for network, since there are no layers")
+		return
+	}
+	op = input
+	for i, l := range n {
+		op, err = l.Calculate(op)
+		if err != nil {
+			err = fmt.Errorf("mlp: layer %d: %v", i, err)
+			return
+		}
+	}
+	return
+}
+
+// GetMemorySize returns the number of weights and biases in the network.
+func (n Network) GetMemorySize() (size int) {
+	for _, l := range n {
+		size += l.GetMemorySize()
+	}
+	return
+}
+
+// GetMemory returns the weights and biases of each layer in turn.
+func (n Network) GetMemory() (memory []float64) {
+	for _, l := range n {
+		memory = append(memory, l.GetMemory()...)
+	}
+	return
+}
+
+// SetMemory sets the weights and biases of each layer. An error is returned if memory isn't the
+// same length as the network's memory.
+func (n Network) SetMemory(memory []float64) error {
+	if len(memory) != n.GetMemorySize() {
+		return fmt.Errorf("mlp: the network has a memory size of %d, but %d values were provided",
+			n.GetMemorySize(), len(memory))
+	}
+	var i int
+	for li, l := range n {
+		j := l.GetMemorySize()
+		if err := l.SetMemory(memory[i : i+j]); err != nil {
+			return fmt.Errorf("mlp: unable to set the memory of layer %d: %v", li, err)
+		}
+		i += j
+	}
+	return nil
+}
+
+// Backpropagate returns the gradient of the error with respect to the network's memory, in the
+// same order as GetMemory, given the input and the gradient of the error with respect to each
+// output.
+func (n Network) Backpropagate(input, outputGradient []float64) (mg []float64, err error) {
+	if len(n) == 0 {
+		err = errors.New("mlp: unable to calculate result for network, since there are no layers")
+		return
+	}
+	if len(outputGradient) != n.OutputCount() {
+		err = fmt.Errorf("mlp: the network has %d outputs, but the output gradient has %d values",
+			n.OutputCount(), len(outputGradient))
+		return
+	}
+
+	// Keep the input, weighted sum and output of each layer.
+	inputs := make([][]float64, len(n))
+	sums := make([][]float64, len(n))
+	outputs := make([][]float64, len(n))
+	x := input
+	for i, l := range n {
+		inputs[i] = x
+		if sums[i], err = l.weightedSum(x); err != nil {
+			err = fmt.Errorf("mlp: layer %d: %v", i, err)
+			return
+		}
+		outputs[i] = make([]float64, len(sums[i]))
+		if err = l.Activation.apply(sums[i], outputs[i]); err != nil {
+			err = fmt.Errorf("mlp: layer %d: %v", i, err)
+			return
+		}
+		x = outputs[i]
+	}
+
+	// Work backwards, filling in the gradient of each layer.
+	mg = make([]float64, n.GetMemorySize())
+	offset := len(mg)
+	da := outputGradient
+	for i := len(n) - 1; i >= 0; i-- {
+		l := n[i]
+		offset -= l.GetMemorySize()
+		dz := make([]float64, len(sums[i]))
+		if err = l.Activation.gradient(sums[i], outputs[i], da, dz); err != nil {
+			err = fmt.Errorf("mlp: layer %d: %v", i, err)
+			return
+		}
+		dx := make([]float64, l.Inputs)
+		for o, g := range dz {
+			for j, xv := range inputs[i] {
+				mg[offset+o*l.Inputs+j] = g * xv
+				dx[j] += g * l.Weights[o*l.Inputs+j]
+			}
+			mg[offset+len(l.Weights)+o] = g
+		}
+		da = dx
+	}
+	return
+}
@@ -0,0 +1,148 @@
+package mlp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+	"github.com/a-h/ml/training"
+)
+
+var _ training.Trainee = Network{}
+var _ training.Differentiable = Network{}
+
+var xor = []training.Data{
+	{Input: []float64{0, 0}, Expected: []float64{0}},
+	{Input: []float64{0, 1}, Expected: []float64{1}},
+	{Input: []float64{1, 0}, Expected: []float64{1}},
+	{Input: []float64{1, 1}, Expected: []float64{0}},
+}
+
+func TestNew(t *testing.T) {
+	n, err := New(InitialisationXavier, []int{2, 3, 4, 2}, ActivationTanh, ActivationSigmoid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(n) != 3 {
+		t.Fatalf("expected 3 layers, got %d", len(n))
+	}
+	expected := []Activation{ActivationTanh, ActivationSigmoid, ActivationSigmoid}
+	for i, l := range n {
+		if l.Activation != expected[i] {
+			t.Errorf("layer %d: expected activation %v, got %v", i, expected[i], l.Activation)
+		}
+	}
+	if size := n.GetMemorySize(); size != 2*3+3+3*4+4+4*2+2 {
+		t.Errorf("expected a memory size of 35, got %d", size)
+	}
+	if n.OutputCount() != 2 {
+		t.Errorf("expected 2 outputs, got %d", n.OutputCount())
+	}
+	op, err := n.Calculate([]float64{0.5, -0.5})
+	if err != nil {
+		t.Fatalf("unexpected error calculating: %v", err)
+	}
+	if len(op) != 2 {
+		t.Errorf("expected 2 outputs, got %v", op)
+	}
+
+	if _, err := New(InitialisationXavier, []int{2}); err == nil {
+		t.Errorf("expected an error for a network without outputs, got nil")
+	}
+	if _, err := NewNetwork(&Layer{Inputs: 2, Biases: make([]float64, 3)}, &Layer{Inputs: 2, Biases: make([]float64, 1)}); err == nil {
+		t.Errorf("expected an error for mismatched layers, got nil")
+	}
+}
+
+func TestNetworkMemory(t *testing.T) {
+	a, err := New(InitialisationUniform, []int{2, 2, 1}, ActivationSigmoid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := New(InitialisationUniform, []int{2, 2, 1}, ActivationSigmoid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := b.SetMemory(a.GetMemory()); err != nil {
+		t.Fatalf("unexpected error setting memory: %v", err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected b == a after setting memory, got %v and %v", a, b)
+	}
+	if err := b.SetMemory(a.GetMemory()[1:]); err == nil {
+		t.Errorf("expected an error setting memory of the wrong length, got nil")
+	}
+}
+
+func TestBackpropagateMatchesNumericGradient(t *testing.T) {
+	n, err := New(InitialisationXavier, []int{2, 3, 2}, ActivationTanh, ActivationSoftmax)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := []training.Data{
+		{Input: []float64{0, 0}, Expected: []float64{0, 1}},
+		{Input: []float64{0, 1}, Expected: []float64{1, 0}},
+		{Input: []float64{1, 0}, Expected: []float64{1, 0}},
+		{Input: []float64{1, 1}, Expected: []float64{0, 1}},
+	}
+	_, actual, err := training.Differentiate(n, d, distance.SumOfSquares)()
+	if err != nil {
+		t.Fatalf("unexpected error calculating gradient: %v", err)
+	}
+	expected, err := training.Gradient(n, d, distance.SumOfSquares)
+	if err != nil {
+		t.Fatalf("unexpected error calculating numeric gradient: %v", err)
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d values, got %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if math.Abs(actual[i]-expected[i]) > 1e-6 {
+			t.Errorf("expected gradient %v, got %v", expected, actual)
+			break
+		}
+	}
+}
+
+func TestTrainingXOR(t *testing.T) {
+	tests := []struct {
+		name       string
+		algorithm  func(memory []float64) training.Algorithm
+		iterations int
+		maxError   float64
+	}{
+		{
+			name: "adam",
+			algorithm: func(memory []float64) training.Algorithm {
+				return training.NewAdam(memory, 0.05)
+			},
+			iterations: 3000,
+			maxError:   1e-3,
+		},
+		{
+			name: "random greedy",
+			algorithm: func(memory []float64) training.Algorithm {
+				return training.NewRandomGreedy(memory)
+			},
+			iterations: 10,
+			maxError:   math.Inf(1),
+		},
+	}
+
+	for _, test := range tests {
+		n, err := New(InitialisationXavier, []int{2, 4, 1}, ActivationTanh, ActivationSigmoid)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		a := test.algorithm(n.GetMemory())
+		_, err = training.Complete(n, xor, a, distance.SumOfSquares, training.StopAfterXIterations(test.iterations))
+		if err != nil {
+			t.Errorf("%s: unexpected error training: %v", test.name, err)
+			continue
+		}
+		if a.BestError() > test.maxError {
+			t.Errorf("%s: expected an error less than %v, got %v", test.name, test.maxError, a.BestError())
+		}
+	}
+}
@@ -22,3 +22,13 @@ func Float64Vector(min, max float64, size int) []float64 {
 	}
 	return op
 }
+
+// Normal returns a normally distributed float64 with the mean and standard deviation.
+func Normal(mean, stddev float64) float64 {
+	return rand.NormFloat64()*stddev + mean
+}
+
+// Intn returns a random int from 0 up to, but not including, n.
+func Intn(n int) int {
+	return rand.Intn(n)
+}
@@ -28,3 +28,37 @@ func TestFloat64(t *testing.T) {
 		}
 	}
 }
+
+func TestNormal(t *testing.T) {
+	var sum, sumOfSquares float64
+	count := 100000
+	for i := 0; i < count; i++ {
+		v := Normal(5, 2)
+		sum += v
+		sumOfSquares += v * v
+	}
+	mean := sum / float64(count)
+	variance := sumOfSquares/float64(count) - mean*mean
+	if mean < 4.95 || mean > 5.05 {
+		t.Errorf("expected a mean of 5, got %f", mean)
+	}
+	if variance < 3.9 || variance > 4.1 {
+		t.Errorf("expected a variance of 4, got %f", variance)
+	}
+}
+
+func TestIntn(t *testing.T) {
+	seen := make([]bool, 5)
+	for i := 0; i < 1000; i++ {
+		v := Intn(5)
+		if v < 0 || v >= 5 {
+			t.Fatalf("expected a value from 0 to 4, got %d", v)
+		}
+		seen[v] = true
+	}
+	for i, s := range seen {
+		if !s {
+			t.Errorf("expected %d to be returned at least once", i)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package rbf
+
+import (
+	"fmt"
+)
+
+// Backpropagate returns the gradient of the error with respect to the node's memory, given the
+// input and the gradient of the error with respect to each output.
+func (n *Node) Backpropagate(input, outputGradient []float64) (mg []float64, err error) {
+	if err = n.validate(input); err != nil {
+		return
+	}
+	if len(n.OutputWeights) != len(outputGradient) {
+		err = fmt.Errorf("rbf: the node has %d outputs, but the output gradient has %d values",
+			len(n.OutputWeights), len(outputGradient))
+		return
+	}
+
+	// output = kernel(u), where u is the distance between the scaled input and the centroid,
+	// scaled by the width.
+	u, dDelta, dWidth := n.distanceGradient(n.delta(input, make([]float64, len(input))))
+	output, du, err := n.Kernel.calculate(u, n.Order)
+	if err != nil {
+		err = fmt.Errorf("rbf: could not calculate %s RBF: %v", n.Kernel.name(), err)
+		return
+	}
+
+	// The gradient of the error with respect to u, through each output weight.
+	var og float64
+	for i, g := range outputGradient {
+		og += g * n.OutputWeights[i]
+	}
+	og *= du
+
+	mg = make([]float64, 0, n.GetMemorySize())
+	// delta = input * weight - centroid
+	for i, iv := range input {
+		mg = append(mg, og*dDelta[i]*iv)
+	}
+	if n.TrainCentroid {
+		for i := range input {
+			mg = append(mg, -og*dDelta[i])
+		}
+	}
+	for _, dw := range dWidth {
+		mg = append(mg, og*dw)
+	}
+	for _, g := range outputGradient {
+		mg = append(mg, g*output)
+	}
+	return
+}
+
+// Backpropagate returns an empty gradient, since the bias has no memory.
+func (b Bias) Backpropagate(input, outputGradient []float64) (mg []float64, err error) {
+	if len(b.Outputs) != len(outputGradient) {
+		err = fmt.Errorf("rbf: the bias has %d outputs, but the output gradient has %d values",
+			len(b.Outputs), len(outputGradient))
+	}
+	return
+}
+
+// Backpropagate returns the gradient of the error with respect to the network's memory, given
+// the input and the gradient of the error with respect to each output.
+func (nodes Network) Backpropagate(input, outputGradient []float64) (mg []float64, err error) {
+	mg = make([]float64, 0, nodes.GetMemorySize())
+	for i, n := range nodes {
+		if _, ok := n.(Trainable); !ok {
+			continue
+		}
+		d, ok := n.(Differentiable)
+		if !ok {
+			err = fmt.Errorf("rbf: node %d is not differentiable", i)
+			return
+		}
+		// The output of the network is the sum of the node outputs, so each node receives the
+		// same output gradient.
+		var nmg []float64
+		nmg, err = d.Backpropagate(input, outputGradient)
+		if err != nil {
+			return
+		}
+		mg = append(mg, nmg...)
+	}
+	return
+}
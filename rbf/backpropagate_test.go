@@ -0,0 +1,107 @@
+package rbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+	"github.com/a-h/ml/training"
+)
+
+var _ training.Differentiable = Network{}
+var _ training.Differentiable = &Node{}
+
+func TestBackpropagateMatchesNumericGradient(t *testing.T) {
+	n, err := NewNetwork(
+		&Node{
+			Width:         1.5,
+			Centroid:      []float64{0.5, 1.0},
+			InputWeights:  []float64{1.0, 0.5},
+			OutputWeights: []float64{2.0, -0.5},
+		},
+		NewBias(2),
+		&Node{
+			Width:         -0.75,
+			Centroid:      []float64{-0.5, 0.0},
+			InputWeights:  []float64{0.5, 1.5},
+			OutputWeights: []float64{-1.0, 1.0},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	d := []training.Data{
+		{Input: []float64{0, 0}, Expected: []float64{0, 1}},
+		{Input: []float64{0, 1}, Expected: []float64{1, 0}},
+		{Input: []float64{1, 0}, Expected: []float64{1, 0}},
+		{Input: []float64{1, 1}, Expected: []float64{0, 1}},
+	}
+	_, actual, err := training.Differentiate(n, d, distance.SumOfSquares)()
+	if err != nil {
+		t.Fatalf("unexpected error calculating gradient: %v", err)
+	}
+	expected, err := training.Gradient(n, d, distance.SumOfSquares)
+	if err != nil {
+		t.Fatalf("unexpected error calculating numeric gradient: %v", err)
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d values, got %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if math.Abs(actual[i]-expected[i]) > 1e-6 {
+			t.Errorf("expected gradient %v, got %v", expected, actual)
+			break
+		}
+	}
+}
+
+func TestBackpropagateErrors(t *testing.T) {
+	n := &Node{
+		Width:         1,
+		Centroid:      []float64{0, 0},
+		InputWeights:  []float64{1, 1},
+		OutputWeights: []float64{1},
+	}
+	if _, err := n.Backpropagate([]float64{0}, []float64{1}); err == nil {
+		t.Errorf("expected an error with the wrong input size")
+	}
+	if _, err := n.Backpropagate([]float64{0, 0}, []float64{1, 1}); err == nil {
+		t.Errorf("expected an error with the wrong output gradient size")
+	}
+	if _, err := NewBias(2).Backpropagate(nil, []float64{1}); err == nil {
+		t.Errorf("expected an error with the wrong output gradient size")
+	}
+	if _, err := (Network{n}).Backpropagate([]float64{0}, []float64{1}); err == nil {
+		t.Errorf("expected the network to return errors from its nodes")
+	}
+}
+
+func TestTrainNetworkWithGradientDescent(t *testing.T) {
+	n, err := NewNetwork(
+		&Node{
+			Width:         1,
+			Centroid:      []float64{0},
+			InputWeights:  []float64{1},
+			OutputWeights: []float64{0.1},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	// Learn a Gaussian bump of height 2 and width 0.5 at x = 0.
+	d := []training.Data{
+		{Input: []float64{-1}, Expected: []float64{2 * math.Exp(-2)}},
+		{Input: []float64{-0.5}, Expected: []float64{2 * math.Exp(-0.5)}},
+		{Input: []float64{0}, Expected: []float64{2}},
+		{Input: []float64{0.5}, Expected: []float64{2 * math.Exp(-0.5)}},
+		{Input: []float64{1}, Expected: []float64{2 * math.Exp(-2)}},
+	}
+	a := training.NewAdam(n.GetMemory(), 0.05)
+	_, err = training.Complete(n, d, a, distance.SumOfSquares, training.StopAfterXIterations(2000))
+	if err != nil {
+		t.Fatalf("unexpected error training: %v", err)
+	}
+	if a.BestError() > 1e-4 {
+		t.Errorf("expected the error to be close to zero, but got %v", a.BestError())
+	}
+}
@@ -0,0 +1,108 @@
+package rbf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// batchAdder is implemented by nodes which can add their output for many inputs to outputs.
+type batchAdder interface {
+	addBatch(inputs, outputs [][]float64) error
+}
+
+// CalculateBatch calculates the output of the node for each of the inputs, writing the results to
+// outputs, which must contain a slice for each input, of length OutputCount. Memory is only
+// allocated once per call, rather than once per input.
+func (n *Node) CalculateBatch(inputs, outputs [][]float64) error {
+	if err := checkBatch(inputs, outputs, n.OutputCount()); err != nil {
+		return err
+	}
+	zero(outputs)
+	return n.addBatch(inputs, outputs)
+}
+
+func (n *Node) addBatch(inputs, outputs [][]float64) error {
+	var buf []float64
+	for i, input := range inputs {
+		if len(buf) != len(input) {
+			buf = make([]float64, len(input))
+		}
+		output, err := n.activationWithBuffer(input, buf)
+		if err != nil {
+			return fmt.Errorf("rbf: input %d: %v", i, err)
+		}
+		for j, outputWeight := range n.OutputWeights {
+			outputs[i][j] += output * outputWeight
+		}
+	}
+	return nil
+}
+
+func (b Bias) addBatch(inputs, outputs [][]float64) error {
+	for i := range inputs {
+		for j, v := range b.Outputs {
+			outputs[i][j] += v
+		}
+	}
+	return nil
+}
+
+// CalculateBatch calculates the output of the network for each of the inputs, writing the results
+// to outputs, which must contain a slice for each input, of length OutputCount. Node and Bias
+// don't allocate memory per input.
+func (nodes Network) CalculateBatch(inputs, outputs [][]float64) error {
+	if len(nodes) == 0 {
+		return errors.New("rbf: Unable to calculate result for RBF network, since there are no nodes")
+	}
+	if err := checkBatch(inputs, outputs, nodes[0].OutputCount()); err != nil {
+		return err
+	}
+	zero(outputs)
+	for i, n := range nodes {
+		if n.OutputCount() != nodes[0].OutputCount() {
+			return fmt.Errorf("rbf: The RBF has been configured with %d output nodes, but node %d has %d output nodes",
+				nodes[0].OutputCount(), i, n.OutputCount())
+		}
+		if ba, ok := n.(batchAdder); ok {
+			if err := ba.addBatch(inputs, outputs); err != nil {
+				return err
+			}
+			continue
+		}
+		for j, input := range inputs {
+			nv, err := n.Calculate(input)
+			if err != nil {
+				return fmt.Errorf("rbf: input %d: %v", j, err)
+			}
+			if len(nv) != len(outputs[j]) {
+				return fmt.Errorf("rbf: The RBF has been configured with %d output nodes, but node %d has %d output nodes",
+					len(outputs[j]), i, len(nv))
+			}
+			for k, v := range nv {
+				outputs[j][k] += v
+			}
+		}
+	}
+	return nil
+}
+
+func checkBatch(inputs, outputs [][]float64, outputCount int) error {
+	if len(inputs) != len(outputs) {
+		return fmt.Errorf("rbf: %d inputs were provided, but there are %d outputs", len(inputs), len(outputs))
+	}
+	for i, output := range outputs {
+		if len(output) != outputCount {
+			return fmt.Errorf("rbf: output %d has a length of %d, but should have a length of %d",
+				i, len(output), outputCount)
+		}
+	}
+	return nil
+}
+
+func zero(outputs [][]float64) {
+	for _, output := range outputs {
+		for j := range output {
+			output[j] = 0
+		}
+	}
+}
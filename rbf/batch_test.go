@@ -0,0 +1,150 @@
+package rbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/random"
+	"github.com/a-h/ml/training"
+)
+
+var _ training.BatchTrainee = Network{}
+var _ training.BatchTrainee = &Node{}
+
+func batchTestNetwork(t testing.TB, inputCount int) Network {
+	covariance := NewNode(inputCount, 2)
+	covariance.UseCovariance()
+	widths := NewNode(inputCount, 2)
+	widths.Kernel = KernelInverseMultiquadric
+	widths.UseWidths()
+	n, err := NewNetwork(NewNode(inputCount, 2), covariance, widths, NewBias(2))
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	return n
+}
+
+func batchTestInputs(count, inputCount int) (inputs, outputs [][]float64) {
+	inputs = make([][]float64, count)
+	outputs = make([][]float64, count)
+	for i := range inputs {
+		inputs[i] = random.Float64Vector(-5, 5, inputCount)
+		outputs[i] = []float64{math.NaN(), math.NaN()}
+	}
+	return
+}
+
+func TestCalculateBatch(t *testing.T) {
+	n := batchTestNetwork(t, 3)
+	inputs, outputs := batchTestInputs(10, 3)
+
+	tests := []struct {
+		name    string
+		trainee training.BatchTrainee
+	}{
+		{name: "network", trainee: n},
+		{name: "node", trainee: n[1].(*Node)},
+	}
+
+	for _, test := range tests {
+		if err := test.trainee.CalculateBatch(inputs, outputs); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		for i, input := range inputs {
+			expected, err := test.trainee.Calculate(input)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			for j := range expected {
+				if math.Abs(outputs[i][j]-expected[j]) > 1e-12 {
+					t.Errorf("%s: input %d: expected %v, got %v", test.name, i, expected, outputs[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestCalculateBatchWithOtherNodes(t *testing.T) {
+	n, err := NewNetwork(&scaleNode{Scale: 2}, NewBias(1))
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	outputs := [][]float64{{0}, {0}}
+	if err := n.CalculateBatch([][]float64{{1}, {3}}, outputs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outputs[0][0] != 3 || outputs[1][0] != 7 {
+		t.Errorf("expected [[3] [7]], got %v", outputs)
+	}
+}
+
+func TestCalculateBatchErrors(t *testing.T) {
+	n := batchTestNetwork(t, 2)
+	tests := []struct {
+		name    string
+		inputs  [][]float64
+		outputs [][]float64
+	}{
+		{
+			name:    "mismatched input and output count",
+			inputs:  [][]float64{{1, 2}, {3, 4}},
+			outputs: [][]float64{{0, 0}},
+		},
+		{
+			name:    "wrong output length",
+			inputs:  [][]float64{{1, 2}},
+			outputs: [][]float64{{0}},
+		},
+		{
+			name:    "wrong input length",
+			inputs:  [][]float64{{1, 2}, {3}},
+			outputs: [][]float64{{0, 0}, {0, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		if err := n.CalculateBatch(test.inputs, test.outputs); err == nil {
+			t.Errorf("%s: expected an error, got nil", test.name)
+		}
+	}
+	if err := (Network{}).CalculateBatch(nil, nil); err == nil {
+		t.Errorf("empty network: expected an error, got nil")
+	}
+}
+
+func TestCalculateBatchAllocations(t *testing.T) {
+	n := batchTestNetwork(t, 3)
+	small, smallOutputs := batchTestInputs(1, 3)
+	large, largeOutputs := batchTestInputs(100, 3)
+	expected := testing.AllocsPerRun(10, func() {
+		n.CalculateBatch(small, smallOutputs)
+	})
+	actual := testing.AllocsPerRun(10, func() {
+		n.CalculateBatch(large, largeOutputs)
+	})
+	if actual != expected {
+		t.Errorf("expected %v allocations regardless of the number of inputs, got %v for 100 inputs", expected, actual)
+	}
+}
+
+func BenchmarkCalculate(b *testing.B) {
+	n := batchTestNetwork(b, 10)
+	inputs, _ := batchTestInputs(100, 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, input := range inputs {
+			n.Calculate(input)
+		}
+	}
+}
+
+func BenchmarkCalculateBatch(b *testing.B) {
+	n := batchTestNetwork(b, 10)
+	inputs, outputs := batchTestInputs(100, 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.CalculateBatch(inputs, outputs)
+	}
+}
@@ -0,0 +1,142 @@
+package rbf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/a-h/ml/internal/format"
+)
+
+// BinaryVersion is the version of the binary format written by WriteBinary.
+const BinaryVersion = 1
+
+const binaryMagic = "RBFN"
+
+// ErrChecksum is returned by ReadBinary when the data doesn't match its checksum.
+var ErrChecksum = format.ErrChecksum
+
+// ErrTruncated is returned by ReadBinary when the data ends early.
+var ErrTruncated = format.ErrTruncated
+
+// Node types in the binary format.
+const (
+	binaryNode       uint8 = 1
+	binaryBias       uint8 = 2
+	binaryRegistered uint8 = 3
+)
+
+const binaryTrainCentroid uint8 = 1
+
+// WriteBinary writes the network to w in a compact binary format. Node and Bias values are
+// written as little-endian float64 values, other node types must have been registered using
+// RegisterNodeType, and are written as JSON. The data ends with a CRC32 checksum. A *Bias is
+// read back as a Bias.
+func WriteBinary(w io.Writer, n Network) error {
+	fw := format.NewWriter(w, binaryMagic, BinaryVersion)
+	fw.Length(len(n))
+	for i, node := range n {
+		switch node := node.(type) {
+		case *Node:
+			fw.Uint8(binaryNode)
+			fw.String(string(node.Kernel))
+			fw.Int64(int64(node.Order))
+			var flags uint8
+			if node.TrainCentroid {
+				flags |= binaryTrainCentroid
+			}
+			fw.Uint8(flags)
+			fw.Float64s(node.InputWeights)
+			fw.Float64s(node.Centroid)
+			fw.Float64(node.Width)
+			fw.Float64s(node.Widths)
+			fw.Float64s(node.Covariance)
+			fw.Float64s(node.OutputWeights)
+		case Bias:
+			fw.Uint8(binaryBias)
+			fw.Float64s(node.Outputs)
+		case *Bias:
+			fw.Uint8(binaryBias)
+			fw.Float64s(node.Outputs)
+		default:
+			nodeTypes.RLock()
+			name, ok := nodeTypes.names[reflect.TypeOf(node)]
+			nodeTypes.RUnlock()
+			if !ok {
+				return fmt.Errorf("rbf: node %d has unregistered type %T", i, node)
+			}
+			b, err := json.Marshal(node)
+			if err != nil {
+				return fmt.Errorf("rbf: unable to marshal node %d: %v", i, err)
+			}
+			fw.Uint8(binaryRegistered)
+			fw.String(name)
+			fw.Bytes(b)
+		}
+	}
+	if err := fw.Close(); err != nil {
+		return fmt.Errorf("rbf: unable to write network: %v", err)
+	}
+	return nil
+}
+
+// ReadBinary reads a network written by WriteBinary from r. ErrChecksum is returned if the data is
+// corrupt, and ErrTruncated if it ends early. Nothing after the checksum is read from r.
+func ReadBinary(r io.Reader) (n Network, err error) {
+	fr, err := format.NewReader(r, binaryMagic, BinaryVersion)
+	if err != nil {
+		return nil, readBinaryError(err)
+	}
+	count := fr.Length()
+	for i := 0; i < count && fr.Err() == nil; i++ {
+		switch t := fr.Uint8(); t {
+		case binaryNode:
+			node := &Node{
+				Kernel: Kernel(fr.String()),
+				Order:  int(fr.Int64()),
+			}
+			node.TrainCentroid = fr.Uint8()&binaryTrainCentroid != 0
+			node.InputWeights = fr.Float64s()
+			node.Centroid = fr.Float64s()
+			node.Width = fr.Float64()
+			node.Widths = fr.Float64s()
+			node.Covariance = fr.Float64s()
+			node.OutputWeights = fr.Float64s()
+			n = append(n, node)
+		case binaryBias:
+			n = append(n, Bias{Outputs: fr.Float64s()})
+		case binaryRegistered:
+			name, b := fr.String(), fr.Bytes()
+			if fr.Err() != nil {
+				break
+			}
+			nodeTypes.RLock()
+			decode, ok := nodeTypes.decoders[name]
+			nodeTypes.RUnlock()
+			if !ok {
+				fr.Fail(fmt.Errorf("rbf: node %d has unknown type %q", i, name))
+				break
+			}
+			node, err := decode(b)
+			if err != nil {
+				fr.Fail(fmt.Errorf("rbf: unable to unmarshal node %d: %v", i, err))
+				break
+			}
+			n = append(n, node)
+		default:
+			fr.Fail(fmt.Errorf("rbf: node %d has unknown type %d", i, t))
+		}
+	}
+	if err = fr.Close(); err != nil {
+		return nil, readBinaryError(err)
+	}
+	return
+}
+
+func readBinaryError(err error) error {
+	if err == ErrChecksum || err == ErrTruncated {
+		return err
+	}
+	return fmt.Errorf("rbf: unable to read network: %v", err)
+}
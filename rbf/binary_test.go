@@ -0,0 +1,145 @@
+package rbf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNetworkBinary(t *testing.T) {
+	expected := jsonTestNetwork(t)
+	// Values which don't survive a round trip through decimal text unless care is taken.
+	expected[0].(*Node).OutputWeights = []float64{math.SmallestNonzeroFloat64, math.Inf(-1)}
+
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, expected); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	actual, err := ReadBinary(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestNetworkBinaryFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbf")
+	if err != nil {
+		t.Fatalf("unexpected error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "network.rbfn")
+
+	expected := Network{NewBias(10)}
+	for i := 0; i < 2000; i++ {
+		n := NewNode(50, 10)
+		if i%2 == 0 {
+			n.UseWidths()
+		}
+		expected = append(expected, n)
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("unexpected error creating file: %v", err)
+	}
+	if err := WriteBinary(f, expected); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("unexpected error closing file: %v", err)
+	}
+
+	f, err = os.Open(name)
+	if err != nil {
+		t.Fatalf("unexpected error opening file: %v", err)
+	}
+	defer f.Close()
+	actual, err := ReadBinary(f)
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected the network read from the file to match the network written")
+	}
+}
+
+func TestNetworkBinaryStream(t *testing.T) {
+	first := jsonTestNetwork(t)
+	second := Network{NewBias(2)}
+	var buf bytes.Buffer
+	for _, n := range []Network{first, second} {
+		if err := WriteBinary(&buf, n); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	for i, expected := range []Network{first, second} {
+		actual, err := ReadBinary(&buf)
+		if err != nil {
+			t.Fatalf("network %d: unexpected error reading: %v", i, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("network %d: expected %v, got %v", i, expected, actual)
+		}
+	}
+}
+
+func TestNetworkBinaryRegisteredType(t *testing.T) {
+	RegisterNodeType("scale", &scaleNode{}, decodeScaleNode)
+	expected := Network{&scaleNode{Scale: 2}, NewBias(1)}
+
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, expected); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	actual, err := ReadBinary(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	if err := WriteBinary(&buf, Network{struct{ ExecutableNode }{}}); err == nil {
+		t.Errorf("expected an error writing an unregistered node type, got nil")
+	}
+}
+
+func TestNetworkBinaryCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, jsonTestNetwork(t)); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	data := buf.Bytes()
+
+	// Change the last byte of the final output weight of the bias.
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-5] ^= 0xFF
+	if _, err := ReadBinary(bytes.NewReader(corrupt)); err != ErrChecksum {
+		t.Errorf("corrupt: expected ErrChecksum, got %v", err)
+	}
+
+	// Change the checksum.
+	corrupt = append([]byte(nil), data...)
+	corrupt[len(corrupt)-1] ^= 0xFF
+	if _, err := ReadBinary(bytes.NewReader(corrupt)); err != ErrChecksum {
+		t.Errorf("checksum: expected ErrChecksum, got %v", err)
+	}
+
+	for i := 6; i < len(data); i++ {
+		if _, err := ReadBinary(bytes.NewReader(data[:i])); err != ErrTruncated {
+			t.Errorf("truncated to %d bytes: expected ErrTruncated, got %v", i, err)
+			break
+		}
+	}
+
+	if _, err := ReadBinary(bytes.NewReader([]byte("{}"))); err == nil {
+		t.Errorf("JSON: expected an error, got nil")
+	}
+}
@@ -0,0 +1,31 @@
+package rbf
+
+import "github.com/a-h/ml/dual"
+
+// DualFunction is a radial basis function which uses dual numbers, so that its exact derivative
+// can be calculated with respect to its input or any of its parameters.
+// Examples are rbf.NewGaussianDual and rbf.NewRickerWaveletDual.
+type DualFunction func(x dual.Number) dual.Number
+
+// NewGaussianDual creates a 1D Gaussian function using dual numbers. To differentiate with
+// respect to a parameter rather than the input, pass it as a dual.Variable and the input as a
+// dual.Constant.
+// a = height of the peak
+// b = center position
+// c = standard deviation
+func NewGaussianDual(a, b, c dual.Number) DualFunction {
+	return func(x dual.Number) dual.Number {
+		numerator := b.Sub(x).Mul(b.Sub(x))
+		denominator := c.Mul(c).Scale(2)
+		return a.Mul(dual.Exp(numerator.Div(denominator).Neg()))
+	}
+}
+
+// NewRickerWaveletDual creates a Ricker wavelet radial basis function using dual numbers.
+func NewRickerWaveletDual(a, b, c dual.Number) DualFunction {
+	return func(x dual.Number) dual.Number {
+		numerator := b.Sub(x).Mul(b.Sub(x))
+		denominator := c.Mul(c).Scale(2)
+		return a.Sub(x.Mul(x)).Mul(dual.Exp(numerator.Div(denominator).Neg()))
+	}
+}
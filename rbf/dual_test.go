@@ -0,0 +1,51 @@
+package rbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/calculus"
+	"github.com/a-h/ml/dual"
+)
+
+func TestDualFunctionsMatch(t *testing.T) {
+	a, b, c := 3.0, 2.0, 0.5
+	tests := []struct {
+		name string
+		f    Function
+		fd   DualFunction
+	}{
+		{
+			name: "gaussian",
+			f:    NewGaussian(a, b, c),
+			fd:   NewGaussianDual(dual.Constant(a), dual.Constant(b), dual.Constant(c)),
+		},
+		{
+			name: "ricker wavelet",
+			f:    NewRickerWavelet(a, b, c),
+			fd:   NewRickerWaveletDual(dual.Constant(a), dual.Constant(b), dual.Constant(c)),
+		},
+	}
+
+	for _, test := range tests {
+		for x := -1.0; x <= 4.0; x += 0.25 {
+			y, dy := dual.Derivative(x, test.fd)
+			if expected := test.f(x); math.Abs(y-expected) > 1e-12 {
+				t.Errorf("%s: at %v expected %v, got %v", test.name, x, expected, y)
+			}
+			if expected, _ := calculus.Derivative(x, test.f); math.Abs(dy-expected) > 1e-7 {
+				t.Errorf("%s: at %v expected derivative %v, got %v", test.name, x, expected, dy)
+			}
+		}
+	}
+}
+
+func TestGaussianDualParameterDerivative(t *testing.T) {
+	// The derivative of the Gaussian with respect to its height is the unit Gaussian.
+	x, b, c := 1.5, 2.0, 0.5
+	f := NewGaussianDual(dual.Variable(3.0), dual.Constant(b), dual.Constant(c))
+	actual := f(dual.Constant(x)).Derivative
+	if expected := NewGaussian(1.0, b, c)(x); math.Abs(actual-expected) > 1e-12 {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -1,6 +1,11 @@
 package rbf
 
-import "testing"
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/calculus"
+)
 
 func TestGaussian(t *testing.T) {
 	actual := NewGaussian(3.0, 2.0, 6.0)(2.0)
@@ -33,3 +38,12 @@ func TestGaussianVectorErrors(t *testing.T) {
 		t.Fatal("expected error executing the function with a nil parameter, but didn't get one")
 	}
 }
+
+func TestGaussianIntegral(t *testing.T) {
+	// The area under a Gaussian is a * c * √(2π), so the Gaussian can be normalised.
+	a, b, c := 3.0, 2.0, 0.5
+	actual := calculus.GaussLegendre(b-10*c, b+10*c, 50, NewGaussian(a, b, c))
+	if expected := a * c * math.Sqrt(2*math.Pi); math.Abs(actual-expected) > 1e-9 {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -10,5 +10,14 @@ type ExecutableNode interface {
 type Trainable interface {
 	GetMemorySize() int
 	GetMemory() []float64
-	SetMemory(m []float64)
+	SetMemory(m []float64) error
+}
+
+// Differentiable defines the behaviour of an item (e.g. node, network) which can calculate the
+// gradient of its memory. It satisfies training.Differentiable.
+type Differentiable interface {
+	// Backpropagate returns the gradient of the error with respect to each value of the memory,
+	// in the same order as GetMemory, given the input and the gradient of the error with respect
+	// to each output.
+	Backpropagate(input, outputGradient []float64) (memoryGradient []float64, err error)
 }
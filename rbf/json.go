@@ -0,0 +1,198 @@
+package rbf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// JSONVersion is the version of the JSON format written by Network.MarshalJSON.
+const JSONVersion = 1
+
+// NodeTypeNode and NodeTypeBias are the type names used to serialise Node and Bias.
+const (
+	NodeTypeNode = "node"
+	NodeTypeBias = "bias"
+)
+
+// A NodeDecoder creates a node from its JSON representation.
+type NodeDecoder func(data []byte) (ExecutableNode, error)
+
+var nodeTypes = struct {
+	sync.RWMutex
+	names    map[reflect.Type]string
+	decoders map[string]NodeDecoder
+}{
+	names:    make(map[reflect.Type]string),
+	decoders: make(map[string]NodeDecoder),
+}
+
+func init() {
+	RegisterNodeType(NodeTypeNode, &Node{}, func(data []byte) (ExecutableNode, error) {
+		n := &Node{}
+		err := json.Unmarshal(data, n)
+		return n, err
+	})
+	decodeBias := func(data []byte) (ExecutableNode, error) {
+		var b Bias
+		err := json.Unmarshal(data, &b)
+		return b, err
+	}
+	// A *Bias is written in the same way as a Bias, and read as a Bias.
+	RegisterNodeType(NodeTypeBias, Bias{}, decodeBias)
+	RegisterNodeType(NodeTypeBias, &Bias{}, decodeBias)
+}
+
+// RegisterNodeType allows nodes of the same type as example to be serialised as part of a Network.
+// The name is written alongside the node's JSON, and used to find the decoder when reading it.
+func RegisterNodeType(name string, example ExecutableNode, decode NodeDecoder) {
+	nodeTypes.Lock()
+	defer nodeTypes.Unlock()
+	nodeTypes.names[reflect.TypeOf(example)] = name
+	nodeTypes.decoders[name] = decode
+}
+
+type jsonNetwork struct {
+	Version int
+	Nodes   []jsonNode
+}
+
+type jsonNode struct {
+	Type string
+	Node json.RawMessage
+}
+
+// MarshalJSON writes the network as JSON, including the type of each node and the format version.
+func (nodes Network) MarshalJSON() ([]byte, error) {
+	nodeTypes.RLock()
+	defer nodeTypes.RUnlock()
+	jn := jsonNetwork{
+		Version: JSONVersion,
+		Nodes:   make([]jsonNode, len(nodes)),
+	}
+	for i, n := range nodes {
+		name, ok := nodeTypes.names[reflect.TypeOf(n)]
+		if !ok {
+			return nil, fmt.Errorf("rbf: node %d has unregistered type %T", i, n)
+		}
+		b, err := json.Marshal(n)
+		if err != nil {
+			return nil, fmt.Errorf("rbf: unable to marshal node %d: %v", i, err)
+		}
+		jn.Nodes[i] = jsonNode{Type: name, Node: b}
+	}
+	return json.Marshal(jn)
+}
+
+// UnmarshalJSON reads a network written by MarshalJSON. JSON arrays of Node and Bias values,
+// which don't include the type of each node, are also accepted.
+func (nodes *Network) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return nodes.unmarshalUntyped(data)
+	}
+	var jn jsonNetwork
+	if err := json.Unmarshal(data, &jn); err != nil {
+		return fmt.Errorf("rbf: unable to unmarshal network: %v", err)
+	}
+	if jn.Version < 1 || jn.Version > JSONVersion {
+		return fmt.Errorf("rbf: unsupported network version %d, expected a version between 1 and %d",
+			jn.Version, JSONVersion)
+	}
+	nodeTypes.RLock()
+	defer nodeTypes.RUnlock()
+	n := make(Network, len(jn.Nodes))
+	for i, node := range jn.Nodes {
+		decode, ok := nodeTypes.decoders[node.Type]
+		if !ok {
+			return fmt.Errorf("rbf: node %d has unknown type %q", i, node.Type)
+		}
+		var err error
+		n[i], err = decode(node.Node)
+		if err != nil {
+			return fmt.Errorf("rbf: unable to unmarshal node %d: %v", i, err)
+		}
+	}
+	*nodes = n
+	return nil
+}
+
+// unmarshalUntyped reads a JSON array of nodes, treating any node with Outputs as a Bias.
+func (nodes *Network) unmarshalUntyped(data []byte) error {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("rbf: unable to unmarshal network: %v", err)
+	}
+	n := make(Network, len(raw))
+	for i, fields := range raw {
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return fmt.Errorf("rbf: unable to unmarshal node %d: %v", i, err)
+		}
+		if _, ok := fields["Outputs"]; ok {
+			var bias Bias
+			err = json.Unmarshal(b, &bias)
+			n[i] = bias
+		} else {
+			node := &Node{}
+			err = json.Unmarshal(b, node)
+			n[i] = node
+		}
+		if err != nil {
+			return fmt.Errorf("rbf: unable to unmarshal node %d: %v", i, err)
+		}
+	}
+	*nodes = n
+	return nil
+}
+
+// SaveFile writes the network to the file as JSON, replacing the file if it exists. The network is
+// written to a temporary file in the same directory, which is then renamed, so that the existing
+// file isn't lost if writing fails.
+func (nodes Network) SaveFile(name string) (err error) {
+	mode := os.FileMode(0644)
+	if fi, err := os.Stat(name); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	f, err := ioutil.TempFile(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("rbf: unable to create file: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if err = json.NewEncoder(f).Encode(nodes); err != nil {
+		return fmt.Errorf("rbf: unable to write network to file: %v", err)
+	}
+	if err = f.Chmod(mode); err != nil {
+		return fmt.Errorf("rbf: unable to set file mode: %v", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("rbf: unable to close file: %v", err)
+	}
+	if err = os.Rename(f.Name(), name); err != nil {
+		return fmt.Errorf("rbf: unable to replace file: %v", err)
+	}
+	return nil
+}
+
+// LoadFile reads a network written by SaveFile.
+func LoadFile(name string) (n Network, err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		err = fmt.Errorf("rbf: unable to open file: %v", err)
+		return
+	}
+	defer f.Close()
+	if err = json.NewDecoder(f).Decode(&n); err != nil {
+		err = fmt.Errorf("rbf: unable to read network from file: %v", err)
+	}
+	return
+}
@@ -0,0 +1,232 @@
+package rbf
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func jsonTestNetwork(t *testing.T) Network {
+	n, err := NewNetwork(
+		&Node{
+			Width:         0.5,
+			Centroid:      []float64{1.0, 2.0},
+			InputWeights:  []float64{3.0, 4.0},
+			OutputWeights: []float64{5.0, 6.0},
+		},
+		&Node{
+			Kernel:        KernelPolyharmonic,
+			Order:         3,
+			TrainCentroid: true,
+			Covariance:    []float64{0.5, 0.1, 0.25},
+			Centroid:      []float64{-1.0, 0.1},
+			InputWeights:  []float64{0.3, 1.0 / 3.0},
+			OutputWeights: []float64{-5.0, 6.0},
+		},
+		NewBias(2),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	return n
+}
+
+func TestNetworkJSON(t *testing.T) {
+	expected := jsonTestNetwork(t)
+	b, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling: %v", err)
+	}
+	var actual Network
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatalf("unexpected error unmarshalling: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	var fromString Network
+	if err := json.Unmarshal([]byte(expected.String()), &fromString); err != nil {
+		t.Fatalf("unexpected error unmarshalling String: %v", err)
+	}
+	if !reflect.DeepEqual(expected, fromString) {
+		t.Errorf("expected %v from String, got %v", expected, fromString)
+	}
+}
+
+func TestNetworkJSONUntyped(t *testing.T) {
+	expected := jsonTestNetwork(t)
+	b, err := json.Marshal([]ExecutableNode(expected))
+	if err != nil {
+		t.Fatalf("unexpected error marshalling: %v", err)
+	}
+	var actual Network
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatalf("unexpected error unmarshalling: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+type scaleNode struct {
+	Scale float64
+}
+
+func (s *scaleNode) Calculate(input []float64) ([]float64, error) {
+	return []float64{s.Scale * input[0]}, nil
+}
+
+func (s *scaleNode) OutputCount() int {
+	return 1
+}
+
+func decodeScaleNode(data []byte) (ExecutableNode, error) {
+	s := &scaleNode{}
+	err := json.Unmarshal(data, s)
+	return s, err
+}
+
+func TestNetworkJSONRegisteredType(t *testing.T) {
+	if _, err := json.Marshal(Network{struct{ ExecutableNode }{}}); err == nil {
+		t.Errorf("expected an error marshalling an unregistered node type, got nil")
+	}
+
+	RegisterNodeType("scale", &scaleNode{}, decodeScaleNode)
+	expected := Network{&scaleNode{Scale: 2}, NewBias(1)}
+	b, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling: %v", err)
+	}
+	var actual Network
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatalf("unexpected error unmarshalling: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestNetworkJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{
+			name: "missing version",
+			json: `{"Nodes":[]}`,
+		},
+		{
+			name: "future version",
+			json: `{"Version":1000,"Nodes":[]}`,
+		},
+		{
+			name: "unknown node type",
+			json: `{"Version":1,"Nodes":[{"Type":"triangle","Node":{}}]}`,
+		},
+		{
+			name: "invalid node",
+			json: `{"Version":1,"Nodes":[{"Type":"bias","Node":{"Outputs":"a"}}]}`,
+		},
+		{
+			name: "invalid JSON",
+			json: `{"Version":1,`,
+		},
+	}
+
+	for _, test := range tests {
+		var n Network
+		if err := json.Unmarshal([]byte(test.json), &n); err == nil {
+			t.Errorf("%s: expected an error, got nil", test.name)
+		}
+	}
+}
+
+func TestNetworkSaveAndLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbf")
+	if err != nil {
+		t.Fatalf("unexpected error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "network.json")
+
+	expected := jsonTestNetwork(t)
+	if err := expected.SaveFile(name); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+	actual, err := LoadFile(name)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	if _, err := LoadFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("expected an error loading a missing file, got nil")
+	}
+}
+
+func TestNetworkSaveFileFailureKeepsExistingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbf")
+	if err != nil {
+		t.Fatalf("unexpected error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "network.json")
+
+	expected := jsonTestNetwork(t)
+	if err := expected.SaveFile(name); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+	if err := (Network{struct{ ExecutableNode }{}}).SaveFile(name); err == nil {
+		t.Fatalf("expected an error saving an unregistered node type, got nil")
+	}
+	actual, err := LoadFile(name)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected the existing file to be kept, got %v", actual)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error reading directory: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected the temporary file to be removed, got %d files", len(files))
+	}
+}
+
+func TestNetworkBiasPointer(t *testing.T) {
+	b := NewBias(2)
+	n := Network{&b}
+	expected := Network{b}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling: %v", err)
+	}
+	var actual Network
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("unexpected error unmarshalling: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("json: expected %v, got %v", expected, actual)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, n); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if actual, err = ReadBinary(&buf); err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("binary: expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,94 @@
+package rbf
+
+import (
+	"fmt"
+	"math"
+)
+
+// Kernel is the radial basis function used by a Node. The zero value is KernelGaussian.
+type Kernel string
+
+const (
+	// KernelGaussian is exp(-r² / 2w²).
+	KernelGaussian Kernel = "gaussian"
+	// KernelRickerWavelet is (1 - r² / w²) exp(-r² / 2w²).
+	KernelRickerWavelet Kernel = "rickerwavelet"
+	// KernelMultiquadric is √(1 + r² / w²).
+	KernelMultiquadric Kernel = "multiquadric"
+	// KernelInverseMultiquadric is 1 / √(1 + r² / w²).
+	KernelInverseMultiquadric Kernel = "inversemultiquadric"
+	// KernelThinPlateSpline is (r / w)² ln(r / w).
+	KernelThinPlateSpline Kernel = "thinplatespline"
+	// KernelPolyharmonic is (r / w)^k for odd orders k, and (r / w)^k ln(r / w) for even orders.
+	KernelPolyharmonic Kernel = "polyharmonic"
+)
+
+// NewKernelVector creates a radial basis function of the distance between the input vector and
+// the centre, using the kernel with width w. order is only used by KernelPolyharmonic.
+func NewKernelVector(k Kernel, centre []float64, w float64, order int) VectorFunction {
+	return func(v []float64) (float64, error) {
+		if len(v) != len(centre) {
+			err := fmt.Errorf("%s: mismached count of comparison vector (%d) to input vector (%d)",
+				k.name(), len(centre), len(v))
+			return 0.0, err
+		}
+		var r2 float64
+		for i, x := range v {
+			r2 += (centre[i] - x) * (centre[i] - x)
+		}
+		phi, _, err := k.calculate(r2/(w*w), order)
+		return phi, err
+	}
+}
+
+func (k Kernel) name() string {
+	if k == "" {
+		return string(KernelGaussian)
+	}
+	return string(k)
+}
+
+// calculate the kernel and its derivative with respect to u, the squared distance divided by
+// the squared width. Using u avoids a square root for most kernels. Where the derivative is
+// undefined at zero, zero is returned.
+func (k Kernel) calculate(u float64, order int) (phi, dphi float64, err error) {
+	switch k {
+	case "", KernelGaussian:
+		phi = math.Exp(-u / 2)
+		dphi = -phi / 2
+	case KernelRickerWavelet:
+		e := math.Exp(-u / 2)
+		phi = (1 - u) * e
+		dphi = e * (u - 3) / 2
+	case KernelMultiquadric:
+		phi = math.Sqrt(1 + u)
+		dphi = 1 / (2 * phi)
+	case KernelInverseMultiquadric:
+		phi = 1 / math.Sqrt(1+u)
+		dphi = -phi * phi * phi / 2
+	case KernelThinPlateSpline:
+		return polyharmonic(u, 2)
+	case KernelPolyharmonic:
+		if order < 1 {
+			err = fmt.Errorf("rbf: the polyharmonic kernel requires an order of at least 1, but got %d", order)
+			return
+		}
+		return polyharmonic(u, order)
+	default:
+		err = fmt.Errorf("rbf: unknown kernel %q", string(k))
+	}
+	return
+}
+
+func polyharmonic(u float64, order int) (phi, dphi float64, err error) {
+	if u == 0 {
+		return
+	}
+	half := float64(order) / 2
+	p := math.Pow(u, half)
+	if order%2 == 1 {
+		return p, half * p / u, nil
+	}
+	// (r / w)^k ln(r / w) = u^(k/2) ln(u) / 2
+	return p * math.Log(u) / 2, (half*p*math.Log(u) + p) / (2 * u), nil
+}
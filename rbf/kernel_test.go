@@ -0,0 +1,159 @@
+package rbf
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/a-h/ml/autodiff"
+	"github.com/a-h/ml/distance"
+	"github.com/a-h/ml/training"
+)
+
+func TestKernels(t *testing.T) {
+	tests := []struct {
+		name     string
+		kernel   Kernel
+		order    int
+		r        float64
+		w        float64
+		expected float64
+	}{
+		{name: "zero value is gaussian", r: 1, w: 2, expected: math.Exp(-1.0 / 8.0)},
+		{name: "gaussian", kernel: KernelGaussian, r: 1, w: 2, expected: math.Exp(-1.0 / 8.0)},
+		{name: "ricker wavelet", kernel: KernelRickerWavelet, r: 1, w: 2, expected: 0.75 * math.Exp(-1.0/8.0)},
+		{name: "multiquadric", kernel: KernelMultiquadric, r: 3, w: 4, expected: 1.25},
+		{name: "inverse multiquadric", kernel: KernelInverseMultiquadric, r: 3, w: 4, expected: 0.8},
+		{name: "thin plate spline", kernel: KernelThinPlateSpline, r: 2, w: 1, expected: 4 * math.Log(2)},
+		{name: "thin plate spline at centre", kernel: KernelThinPlateSpline, r: 0, w: 1, expected: 0},
+		{name: "polyharmonic order 1", kernel: KernelPolyharmonic, order: 1, r: 3, w: 2, expected: 1.5},
+		{name: "polyharmonic order 3", kernel: KernelPolyharmonic, order: 3, r: 2, w: 1, expected: 8},
+		{name: "polyharmonic order 4", kernel: KernelPolyharmonic, order: 4, r: 2, w: 1, expected: 16 * math.Log(2)},
+	}
+
+	for _, test := range tests {
+		f := NewKernelVector(test.kernel, []float64{1, 1}, test.w, test.order)
+		actual, err := f([]float64{1 + test.r, 1})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if math.Abs(actual-test.expected) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestKernelErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		kernel Kernel
+		order  int
+		input  []float64
+	}{
+		{name: "unknown kernel", kernel: Kernel("cubic"), input: []float64{1, 1}},
+		{name: "polyharmonic without an order", kernel: KernelPolyharmonic, input: []float64{1, 1}},
+		{name: "mismatched input", kernel: KernelMultiquadric, input: []float64{1}},
+	}
+
+	for _, test := range tests {
+		n := &Node{
+			Kernel:        test.kernel,
+			Order:         test.order,
+			Width:         1,
+			Centroid:      []float64{0, 0},
+			InputWeights:  []float64{1, 1},
+			OutputWeights: []float64{1},
+		}
+		if _, err := n.Calculate(test.input); err == nil {
+			t.Errorf("%s: expected an error from Calculate, got nil", test.name)
+		}
+		if _, err := n.Backpropagate(test.input, []float64{1}); err == nil {
+			t.Errorf("%s: expected an error from Backpropagate, got nil", test.name)
+		}
+		tape := autodiff.NewTape()
+		if _, err := n.Record(tape, tape.Variables(n.GetMemory()), test.input); err == nil {
+			t.Errorf("%s: expected an error from Record, got nil", test.name)
+		}
+	}
+}
+
+func TestKernelGradients(t *testing.T) {
+	kernels := []struct {
+		kernel Kernel
+		order  int
+	}{
+		{kernel: KernelGaussian},
+		{kernel: KernelRickerWavelet},
+		{kernel: KernelMultiquadric},
+		{kernel: KernelInverseMultiquadric},
+		{kernel: KernelThinPlateSpline},
+		{kernel: KernelPolyharmonic, order: 3},
+		{kernel: KernelPolyharmonic, order: 4},
+	}
+	d := []training.Data{
+		{Input: []float64{0, 0}, Expected: []float64{0, 1}},
+		{Input: []float64{0, 1}, Expected: []float64{1, 0}},
+		{Input: []float64{1, 0}, Expected: []float64{1, 0}},
+		{Input: []float64{1, 1}, Expected: []float64{0, 1}},
+	}
+
+	for _, k := range kernels {
+		n, err := NewNetwork(
+			&Node{
+				Kernel:        k.kernel,
+				Order:         k.order,
+				Width:         1.5,
+				Centroid:      []float64{0.5, 1.0},
+				InputWeights:  []float64{1.0, 0.5},
+				OutputWeights: []float64{2.0, -0.5},
+			},
+			NewBias(2),
+		)
+		if err != nil {
+			t.Fatalf("%s: unexpected error creating network: %v", k.kernel, err)
+		}
+		expected, err := training.Gradient(n, d, distance.SumOfSquares)
+		if err != nil {
+			t.Fatalf("%s: unexpected error calculating numeric gradient: %v", k.kernel, err)
+		}
+		_, backpropagated, err := training.Differentiate(n, d, distance.SumOfSquares)()
+		if err != nil {
+			t.Fatalf("%s: unexpected error backpropagating: %v", k.kernel, err)
+		}
+		_, recorded, err := autodiff.Gradient(n, d, autodiff.SumOfSquares)
+		if err != nil {
+			t.Fatalf("%s: unexpected error recording: %v", k.kernel, err)
+		}
+		for i := range expected {
+			if math.Abs(backpropagated[i]-expected[i]) > 1e-6 || math.Abs(recorded[i]-expected[i]) > 1e-6 {
+				t.Errorf("%s: expected gradient %v, got %v (backpropagated) and %v (recorded)",
+					k.kernel, expected, backpropagated, recorded)
+				break
+			}
+		}
+	}
+}
+
+func TestKernelSurvivesJSON(t *testing.T) {
+	expected := &Node{
+		Kernel:        KernelPolyharmonic,
+		Order:         3,
+		Width:         1.5,
+		Centroid:      []float64{0.5, 1.0},
+		InputWeights:  []float64{1.0, 0.5},
+		OutputWeights: []float64{2.0},
+	}
+	b, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling: %v", err)
+	}
+	actual := &Node{}
+	if err := json.Unmarshal(b, actual); err != nil {
+		t.Fatalf("unexpected error unmarshalling: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
@@ -0,0 +1,272 @@
+package rbf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/a-h/ml/clustering"
+	"github.com/a-h/ml/distance"
+	"github.com/a-h/ml/training"
+)
+
+// LeastSquares trains an RBF network in a single pass. The centroids of the nodes are placed
+// using clustering.KMeans, the width of each node is set from the distance to the nearest other
+// centroid, and the output weights are solved using regularised linear least squares.
+type LeastSquares struct {
+	// Nodes is the number of RBF nodes to create.
+	Nodes int
+	// Overlap multiplies the distance to the nearest centroid to give the node's width. If zero,
+	// 1 is used.
+	Overlap float64
+	// Kernel used by each node. If empty, KernelGaussian is used.
+	Kernel Kernel
+	// Order of the kernel, only used by KernelPolyharmonic.
+	Order int
+	// Regularisation penalises large output weights (ridge regression). Zero is ordinary least squares.
+	Regularisation float64
+	// Distance used to cluster the input data. If nil, distance.Euclidean is used.
+	Distance distance.Function
+}
+
+// Train creates a network of RBF nodes and a bias node, fitted to the data.
+func (ls LeastSquares) Train(d []training.Data) (n Network, err error) {
+	if len(d) == 0 {
+		err = errors.New("rbf: no training data provided")
+		return
+	}
+	if ls.Nodes <= 0 || ls.Nodes > len(d) {
+		err = fmt.Errorf("rbf: the number of nodes must be between 1 and the amount of training data (%d), but was %d",
+			len(d), ls.Nodes)
+		return
+	}
+	dist := ls.Distance
+	if dist == nil {
+		dist = distance.Euclidean
+	}
+	overlap := ls.Overlap
+	if overlap == 0 {
+		overlap = 1
+	}
+
+	// Place the centroids.
+	inputs := make([]clustering.Vector, len(d))
+	for i, td := range d {
+		inputs[i] = td.Input
+	}
+	assignment, err := clustering.KMeans(inputs, ls.Nodes, dist)
+	if err != nil {
+		err = fmt.Errorf("rbf: failed to cluster training data: %v", err)
+		return
+	}
+	clusters, err := clustering.Assign(inputs, assignment)
+	if err != nil {
+		err = fmt.Errorf("rbf: failed to cluster training data: %v", err)
+		return
+	}
+	var centroids []clustering.Vector
+	for _, c := range clusters {
+		if len(c) == 0 {
+			continue
+		}
+		centroid, _ := clustering.Centroid(c)
+		centroids = append(centroids, centroid)
+	}
+
+	// Set the widths.
+	widths, err := nearestCentroidWidths(centroids, overlap, dist)
+	if err != nil {
+		return
+	}
+
+	outputCount := len(d[0].Expected)
+	for i, c := range centroids {
+		inputWeights := make([]float64, len(c))
+		for j := range inputWeights {
+			inputWeights[j] = 1
+		}
+		n = append(n, &Node{
+			InputWeights:  inputWeights,
+			Centroid:      c,
+			Width:         widths[i],
+			OutputWeights: make([]float64, outputCount),
+			Kernel:        ls.Kernel,
+			Order:         ls.Order,
+		})
+	}
+	n = append(n, NewBias(outputCount))
+
+	err = SolveOutputWeights(n, d, ls.Regularisation)
+	return
+}
+
+// nearestCentroidWidths returns a width for each centroid of the distance to the nearest other
+// centroid, multiplied by the overlap. With a single centroid, the width is the overlap.
+func nearestCentroidWidths(centroids []clustering.Vector, overlap float64, dist distance.Function) ([]float64, error) {
+	widths := make([]float64, len(centroids))
+	for i, ci := range centroids {
+		nearest := math.MaxFloat64
+		for j, cj := range centroids {
+			if i == j {
+				continue
+			}
+			cd, err := dist(ci, cj)
+			if err != nil {
+				return nil, fmt.Errorf("rbf: failed to calculate distance between centroids: %v", err)
+			}
+			if cd > 0 && cd < nearest {
+				nearest = cd
+			}
+		}
+		if nearest == math.MaxFloat64 {
+			nearest = 1
+		}
+		widths[i] = nearest * overlap
+	}
+	return widths, nil
+}
+
+// SolveOutputWeights sets the output weights of each Node, and the outputs of each Bias, in the
+// network to minimise the sum of squared errors over the data, using QR decomposition. The
+// regularisation (ridge regression) penalises large weights, zero is ordinary least squares.
+func SolveOutputWeights(n Network, d []training.Data, regularisation float64) error {
+	if len(d) == 0 {
+		return errors.New("rbf: no training data provided")
+	}
+	if regularisation < 0 {
+		return fmt.Errorf("rbf: regularisation must not be negative, but was %v", regularisation)
+	}
+	// Each column of the design matrix is the output of a node before it's multiplied by the
+	// output weights.
+	columns := len(n)
+	rows := len(d)
+	if regularisation > 0 {
+		rows += columns
+	}
+	a := make([][]float64, rows)
+	b := make([][]float64, rows)
+	outputCount := len(d[0].Expected)
+	for i, td := range d {
+		if len(td.Expected) != outputCount {
+			return fmt.Errorf("rbf: training data %d has %d expected values, but expected %d", i, len(td.Expected), outputCount)
+		}
+		a[i] = make([]float64, columns)
+		b[i] = td.Expected
+		for j, node := range n {
+			switch node := node.(type) {
+			case *Node:
+				if node.OutputCount() != outputCount {
+					return fmt.Errorf("rbf: node %d has %d outputs, but expected %d", j, node.OutputCount(), outputCount)
+				}
+				v, err := node.activation(td.Input)
+				if err != nil {
+					return err
+				}
+				a[i][j] = v
+			case Bias:
+				if node.OutputCount() != outputCount {
+					return fmt.Errorf("rbf: node %d has %d outputs, but expected %d", j, node.OutputCount(), outputCount)
+				}
+				a[i][j] = 1
+			default:
+				return fmt.Errorf("rbf: unable to solve output weights of node %d with type %T", j, node)
+			}
+		}
+	}
+	// Regularise by adding a row of sqrt(regularisation) for each column, with an expected value of zero.
+	for j := 0; j < rows-len(d); j++ {
+		a[len(d)+j] = make([]float64, columns)
+		a[len(d)+j][j] = math.Sqrt(regularisation)
+		b[len(d)+j] = make([]float64, outputCount)
+	}
+
+	weights, err := leastSquares(a, b)
+	if err != nil {
+		return err
+	}
+	for j, node := range n {
+		switch node := node.(type) {
+		case *Node:
+			copy(node.OutputWeights, weights[j])
+		case Bias:
+			copy(node.Outputs, weights[j])
+		}
+	}
+	return nil
+}
+
+// leastSquares returns x which minimises ||ax - b||² using Householder QR decomposition.
+// a must have at least as many rows as columns.
+func leastSquares(a, b [][]float64) ([][]float64, error) {
+	m, k, p := len(a), len(a[0]), len(b[0])
+	if m < k {
+		return nil, fmt.Errorf("rbf: unable to solve least squares with %d rows of data for %d weights, add more data or regularisation", m, k)
+	}
+	// Copy the inputs, since they're modified in place.
+	r := make([][]float64, m)
+	qtb := make([][]float64, m)
+	for i := range a {
+		r[i] = append([]float64(nil), a[i]...)
+		qtb[i] = append([]float64(nil), b[i]...)
+	}
+	v := make([]float64, m)
+	var largest float64
+	for j := 0; j < k; j++ {
+		var norm float64
+		for i := j; i < m; i++ {
+			norm += r[i][j] * r[i][j]
+		}
+		norm = math.Sqrt(norm)
+		largest = math.Max(largest, norm)
+		if norm == 0 {
+			continue
+		}
+		// Reflect column j onto the diagonal.
+		alpha := -math.Copysign(norm, r[j][j])
+		var vv float64
+		for i := j; i < m; i++ {
+			v[i] = r[i][j]
+			if i == j {
+				v[i] -= alpha
+			}
+			vv += v[i] * v[i]
+		}
+		if vv == 0 {
+			continue
+		}
+		reflect := func(x [][]float64, c int) {
+			var dot float64
+			for i := j; i < m; i++ {
+				dot += v[i] * x[i][c]
+			}
+			f := 2 * dot / vv
+			for i := j; i < m; i++ {
+				x[i][c] -= f * v[i]
+			}
+		}
+		for c := j; c < k; c++ {
+			reflect(r, c)
+		}
+		for c := 0; c < p; c++ {
+			reflect(qtb, c)
+		}
+	}
+	// Solve rx = qtb by back substitution.
+	x := make([][]float64, k)
+	for i := range x {
+		x[i] = make([]float64, p)
+	}
+	for i := k - 1; i >= 0; i-- {
+		if math.Abs(r[i][i]) <= 1e-12*largest {
+			return nil, errors.New("rbf: unable to solve least squares, since the node outputs are linearly dependent, add regularisation")
+		}
+		for c := 0; c < p; c++ {
+			s := qtb[i][c]
+			for l := i + 1; l < k; l++ {
+				s -= r[i][l] * x[l][c]
+			}
+			x[i][c] = s / r[i][i]
+		}
+	}
+	return x, nil
+}
@@ -0,0 +1,115 @@
+package rbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+	"github.com/a-h/ml/training"
+)
+
+func TestLeastSquaresTrain(t *testing.T) {
+	var d []training.Data
+	for x := -3.0; x <= 3.0; x += 0.1 {
+		d = append(d, training.Data{
+			Input:    []float64{x},
+			Expected: []float64{math.Sin(x)},
+		})
+	}
+	n, err := LeastSquares{Nodes: 12, Regularisation: 1e-6}.Train(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(n) != 13 {
+		t.Errorf("expected 12 nodes and a bias, got %d nodes", len(n))
+	}
+	var total float64
+	for _, td := range d {
+		actual, err := n.Calculate(td.Input)
+		if err != nil {
+			t.Fatalf("unexpected error calculating: %v", err)
+		}
+		e, _ := distance.SumOfSquares(actual, td.Expected)
+		if e > 0.01 {
+			t.Errorf("for input %v expected %v, got %v", td.Input, td.Expected, actual)
+		}
+		total += e
+	}
+	if mse := total / float64(len(d)); mse > 1e-3 {
+		t.Errorf("expected a mean squared error below 0.001, got %v", mse)
+	}
+}
+
+func TestLeastSquaresTrainErrors(t *testing.T) {
+	d := []training.Data{
+		{Input: []float64{0}, Expected: []float64{0}},
+		{Input: []float64{1}, Expected: []float64{1}},
+	}
+	if _, err := (LeastSquares{Nodes: 3}).Train(d); err == nil {
+		t.Errorf("expected an error with more nodes than data")
+	}
+	if _, err := (LeastSquares{Nodes: 1}).Train(nil); err == nil {
+		t.Errorf("expected an error with no data")
+	}
+}
+
+func TestSolveOutputWeights(t *testing.T) {
+	n := Network{
+		&Node{
+			Width:         1,
+			Centroid:      []float64{-1, 0},
+			InputWeights:  []float64{1, 1},
+			OutputWeights: []float64{0, 0},
+		},
+		&Node{
+			Width:         0.5,
+			Centroid:      []float64{1, 1},
+			InputWeights:  []float64{1, 1},
+			OutputWeights: []float64{0, 0},
+		},
+		Bias{Outputs: []float64{0, 0}},
+	}
+	expected := Network{
+		&Node{
+			Width:         1,
+			Centroid:      []float64{-1, 0},
+			InputWeights:  []float64{1, 1},
+			OutputWeights: []float64{2, -1},
+		},
+		&Node{
+			Width:         0.5,
+			Centroid:      []float64{1, 1},
+			InputWeights:  []float64{1, 1},
+			OutputWeights: []float64{0.5, 3},
+		},
+		Bias{Outputs: []float64{-0.25, 0.75}},
+	}
+	var d []training.Data
+	for x := -2.0; x <= 2.0; x += 0.5 {
+		for y := -2.0; y <= 2.0; y += 0.5 {
+			op, _ := expected.Calculate([]float64{x, y})
+			d = append(d, training.Data{Input: []float64{x, y}, Expected: op})
+		}
+	}
+	if err := SolveOutputWeights(n, d, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, want := n.GetMemory(), expected.GetMemory()
+	for i := range want {
+		if math.Abs(actual[i]-want[i]) > 1e-9 {
+			t.Fatalf("expected memory %v, got %v", want, actual)
+		}
+	}
+	if actual, want := n[2].(Bias).Outputs, expected[2].(Bias).Outputs; math.Abs(actual[0]-want[0]) > 1e-9 || math.Abs(actual[1]-want[1]) > 1e-9 {
+		t.Errorf("expected bias %v, got %v", want, actual)
+	}
+
+	// Identical nodes can't be solved without regularisation.
+	duplicate := Network{n[0], n[0]}
+	if err := SolveOutputWeights(duplicate, d, 0); err == nil {
+		t.Errorf("expected an error solving linearly dependent nodes")
+	}
+	if err := SolveOutputWeights(duplicate, d, 0.01); err != nil {
+		t.Errorf("unexpected error with regularisation: %v", err)
+	}
+}
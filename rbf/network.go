@@ -90,14 +90,22 @@ func (nodes Network) GetMemory() (memory []float64) {
 	return
 }
 
-// SetMemory sets the tunable parameters of the network.
-func (nodes Network) SetMemory(memory []float64) {
+// SetMemory sets the tunable parameters of the network. An error is returned if memory isn't the
+// same length as the network's memory.
+func (nodes Network) SetMemory(memory []float64) error {
+	if len(memory) != nodes.GetMemorySize() {
+		return fmt.Errorf("rbf: the network has a memory size of %d, but %d values were provided",
+			nodes.GetMemorySize(), len(memory))
+	}
 	var i int
-	for _, n := range nodes {
+	for ni, n := range nodes {
 		if t, ok := n.(Trainable); ok {
 			j := t.GetMemorySize()
-			t.SetMemory(memory[i : i+j])
+			if err := t.SetMemory(memory[i : i+j]); err != nil {
+				return fmt.Errorf("rbf: unable to set the memory of node %d: %v", ni, err)
+			}
 			i += j
 		}
 	}
+	return nil
 }
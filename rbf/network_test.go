@@ -40,11 +40,21 @@ func TestNetworkMemory(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create network b: %v", err)
 	}
-	b.SetMemory(a.GetMemory())
+	if err := b.SetMemory(a.GetMemory()); err != nil {
+		t.Fatalf("unexpected error setting memory: %v", err)
+	}
 
 	if !reflect.DeepEqual(a, b) {
 		t.Errorf("Expected b == a after setting memory, but got false.")
 		t.Errorf("a: %v", a)
 		t.Errorf("b: %v", b)
 	}
+
+	expected := b.GetMemory()
+	if err := b.SetMemory(expected[1:]); err == nil {
+		t.Errorf("expected an error setting memory of the wrong length, got nil")
+	}
+	if actual := b.GetMemory(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the memory to be unchanged as %v, got %v", expected, actual)
+	}
 }
@@ -24,6 +24,18 @@ type Node struct {
 	// RBF function parameters.
 	Width         float64
 	OutputWeights []float64
+	// Kernel is the radial basis function. If empty, KernelGaussian is used.
+	Kernel Kernel `json:",omitempty"`
+	// Order of the KernelPolyharmonic kernel.
+	Order int `json:",omitempty"`
+	// TrainCentroid includes the Centroid in the node's memory, so that training algorithms can
+	// move it.
+	TrainCentroid bool `json:",omitempty"`
+	// Widths gives each input its own width (a diagonal covariance). If set, Width is not used.
+	Widths []float64 `json:",omitempty"`
+	// Covariance is the lower triangle of the Cholesky factor L of the covariance matrix LLᵀ,
+	// packed row by row. If set, Width and Widths are not used.
+	Covariance []float64 `json:",omitempty"`
 }
 
 func (n Node) String() string {
@@ -36,22 +48,10 @@ func (n Node) String() string {
 
 // Calculate the output of the node.
 func (n *Node) Calculate(input []float64) (op []float64, err error) {
-	if len(n.InputWeights) != len(input) {
-		err = fmt.Errorf("rbf: the input vector has a length of %d values and should have the same number of input weights, but we have %d node input weights",
-			len(input), len(n.InputWeights))
-		return
-	}
-
-	// Scale the input against the node's weights
-	scaledInput := make([]float64, len(input))
-	for i, iv := range input {
-		scaledInput[i] = iv * n.InputWeights[i]
-	}
-
 	// Scale the distance using the RBF function then multiply by the scalar output weights.
-	output, err := NewGaussianVector(1.0, n.Centroid, n.Width)(scaledInput)
+	output, err := n.activation(input)
 	if err != nil {
-		err = fmt.Errorf("rbf: could not calculate gaussian RBF: %v", err)
+		return
 	}
 	op = make([]float64, len(n.OutputWeights))
 	for i, outputWeight := range n.OutputWeights {
@@ -67,25 +67,119 @@ func (n *Node) OutputCount() int {
 
 // GetMemorySize returns the size of the node's internal state.
 func (n *Node) GetMemorySize() int {
-	return len(n.InputWeights) + 1 + len(n.OutputWeights) // 1 = a value for n.Width
+	return len(n.InputWeights) + n.centroidSize() + n.widthSize() + len(n.OutputWeights)
 }
 
-// GetMemory returns the node's internal state as an array.
+// GetMemory returns the node's internal state as an array, in the order InputWeights, Centroid
+// (if TrainCentroid is set), the width (Width, Widths or Covariance), then OutputWeights.
 func (n *Node) GetMemory() (op []float64) {
 	//TODO: Benchmark this approach and check that it's OK. I think it is given Go's slice internals.
 	op = append(op, n.InputWeights...)
-	op = append(op, n.Width)
+	if n.TrainCentroid {
+		op = append(op, n.Centroid...)
+	}
+	switch {
+	case len(n.Covariance) > 0:
+		op = append(op, n.Covariance...)
+	case len(n.Widths) > 0:
+		op = append(op, n.Widths...)
+	default:
+		op = append(op, n.Width)
+	}
 	op = append(op, n.OutputWeights...)
 	return
 }
 
-// SetMemory updates the node's internal state.
-func (n *Node) SetMemory(m []float64) {
-	//TODO: Add error handling here, check the lengths etc. Lots of opportunities to panic here.
+// SetMemory updates the node's internal state. An error is returned if m isn't the same length
+// as the node's memory.
+func (n *Node) SetMemory(m []float64) error {
+	if len(m) != n.GetMemorySize() {
+		return fmt.Errorf("rbf: the node has a memory size of %d, but %d values were provided",
+			n.GetMemorySize(), len(m))
+	}
 	var index int
 	n.InputWeights = m[index:len(n.InputWeights)]
 	index = len(n.InputWeights)
-	n.Width = m[index]
-	index++
+	if n.TrainCentroid {
+		n.Centroid = m[index : index+len(n.Centroid)]
+		index += len(n.Centroid)
+	}
+	switch {
+	case len(n.Covariance) > 0:
+		n.Covariance = m[index : index+len(n.Covariance)]
+		index += len(n.Covariance)
+	case len(n.Widths) > 0:
+		n.Widths = m[index : index+len(n.Widths)]
+		index += len(n.Widths)
+	default:
+		n.Width = m[index]
+		index++
+	}
 	n.OutputWeights = m[index:]
+	return nil
+}
+
+func (n *Node) centroidSize() int {
+	if n.TrainCentroid {
+		return len(n.Centroid)
+	}
+	return 0
+}
+
+func (n *Node) widthSize() int {
+	switch {
+	case len(n.Covariance) > 0:
+		return len(n.Covariance)
+	case len(n.Widths) > 0:
+		return len(n.Widths)
+	}
+	return 1
+}
+
+// validate checks that the input and the node's parameters have matching lengths.
+func (n *Node) validate(input []float64) error {
+	if len(n.InputWeights) != len(input) {
+		return fmt.Errorf("rbf: the input vector has a length of %d values and should have the same number of input weights, but we have %d node input weights",
+			len(input), len(n.InputWeights))
+	}
+	if len(n.Centroid) != len(input) {
+		return fmt.Errorf("rbf: could not calculate %s RBF: mismached count of comparison vector (%d) to input vector (%d)",
+			n.Kernel.name(), len(n.Centroid), len(input))
+	}
+	if len(n.Covariance) > 0 && len(n.Covariance) != triangleSize(len(input)) {
+		return fmt.Errorf("rbf: a covariance for %d inputs should have %d values, but has %d",
+			len(input), triangleSize(len(input)), len(n.Covariance))
+	}
+	if len(n.Covariance) == 0 && len(n.Widths) > 0 && len(n.Widths) != len(input) {
+		return fmt.Errorf("rbf: the node has %d widths, but the input vector has a length of %d values",
+			len(n.Widths), len(input))
+	}
+	return nil
+}
+
+// delta writes the difference between the input scaled against the node's weights and the
+// centroid to d, and returns it.
+func (n *Node) delta(input, d []float64) []float64 {
+	for i, iv := range input {
+		d[i] = iv*n.InputWeights[i] - n.Centroid[i]
+	}
+	return d
+}
+
+// activation returns the output of the node's RBF before it's multiplied by the output weights.
+func (n *Node) activation(input []float64) (float64, error) {
+	return n.activationWithBuffer(input, make([]float64, len(input)))
+}
+
+// activationWithBuffer is activation, using buf, which must be the same length as the input, in
+// place of allocating memory.
+func (n *Node) activationWithBuffer(input, buf []float64) (float64, error) {
+	if err := n.validate(input); err != nil {
+		return 0, err
+	}
+	output, _, err := n.Kernel.calculate(n.distance(n.delta(input, buf)), n.Order)
+	if err != nil {
+		return 0, fmt.Errorf("rbf: could not calculate %s RBF: %v", n.Kernel.name(), err)
+	}
+	return output, nil
 }
@@ -55,7 +55,9 @@ func TestNodeMemory(t *testing.T) {
 		OutputWeights: []float64{-1, -1},
 	}
 
-	b.SetMemory(a.GetMemory())
+	if err := b.SetMemory(a.GetMemory()); err != nil {
+		t.Fatalf("unexpected error setting memory: %v", err)
+	}
 
 	if !reflect.DeepEqual(a, b) {
 		t.Errorf("Expected b == a after setting memory, but got false.")
@@ -63,3 +65,39 @@ func TestNodeMemory(t *testing.T) {
 		t.Errorf("b: %v", b)
 	}
 }
+
+func TestNodeSetMemoryErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		memory []float64
+	}{
+		{
+			name:   "nil memory",
+			memory: nil,
+		},
+		{
+			name:   "too few values",
+			memory: []float64{1, 2, 3},
+		},
+		{
+			name:   "too many values",
+			memory: []float64{1, 2, 3, 4, 5, 6, 7},
+		},
+	}
+
+	for _, test := range tests {
+		n := &Node{
+			Width:         0.5,
+			Centroid:      []float64{1.0, 2.0},
+			InputWeights:  []float64{3.0, 4.0},
+			OutputWeights: []float64{5.0, 6.0},
+		}
+		expected := n.GetMemory()
+		if err := n.SetMemory(test.memory); err == nil {
+			t.Errorf("%s: expected an error, got nil", test.name)
+		}
+		if actual := n.GetMemory(); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected the memory to be unchanged as %v, got %v", test.name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package rbf
+
+import (
+	"fmt"
+
+	"github.com/a-h/ml/autodiff"
+)
+
+// Recordable defines the behaviour of a node which can record its calculation on an autodiff.Tape,
+// so that the gradient of its output with respect to its memory can be calculated.
+type Recordable interface {
+	Record(t *autodiff.Tape, memory []autodiff.Var, input []float64) (output []autodiff.Var, err error)
+}
+
+// Record the calculation of the node on the tape, using memory in place of the node's own
+// memory. memory must be in the same order as GetMemory.
+func (n *Node) Record(t *autodiff.Tape, memory []autodiff.Var, input []float64) (op []autodiff.Var, err error) {
+	if len(memory) != n.GetMemorySize() {
+		err = fmt.Errorf("rbf: the node has a memory size of %d, but %d values were provided",
+			n.GetMemorySize(), len(memory))
+		return
+	}
+	if err = n.validate(input); err != nil {
+		return
+	}
+	index := len(n.InputWeights)
+	inputWeights := memory[:index]
+	centroid := autodiff.Constants(n.Centroid)
+	if n.TrainCentroid {
+		centroid = memory[index : index+len(n.Centroid)]
+		index += len(n.Centroid)
+	}
+	widths := memory[index : index+n.widthSize()]
+	outputWeights := memory[index+n.widthSize():]
+
+	// Kernel of the distance between the centroid and the input scaled against the node's weights.
+	delta := make([]autodiff.Var, len(input))
+	for i, iv := range input {
+		delta[i] = inputWeights[i].Scale(iv).Sub(centroid[i])
+	}
+	u := n.recordDistance(delta, widths)
+	phi, du, err := n.Kernel.calculate(u.Value, n.Order)
+	if err != nil {
+		err = fmt.Errorf("rbf: could not calculate %s RBF: %v", n.Kernel.name(), err)
+		return
+	}
+	output := autodiff.Apply(u, phi, du)
+
+	op = make([]autodiff.Var, len(outputWeights))
+	for i, outputWeight := range outputWeights {
+		op[i] = output.Mul(outputWeight)
+	}
+	return
+}
+
+// recordDistance records the calculation of u, the squared distance scaled by the width
+// parameters, as described by distance.
+func (n *Node) recordDistance(delta, widths []autodiff.Var) autodiff.Var {
+	u := autodiff.Constant(0)
+	switch {
+	case len(n.Covariance) > 0:
+		// Solve Ly = delta by forward substitution.
+		y := make([]autodiff.Var, len(delta))
+		for i := range delta {
+			sum := delta[i]
+			for j := 0; j < i; j++ {
+				sum = sum.Sub(widths[triangleIndex(i, j)].Mul(y[j]))
+			}
+			y[i] = sum.Div(widths[triangleIndex(i, i)])
+			u = u.Add(y[i].Mul(y[i]))
+		}
+	case len(n.Widths) > 0:
+		for i, d := range delta {
+			scaled := d.Div(widths[i])
+			u = u.Add(scaled.Mul(scaled))
+		}
+	default:
+		for _, d := range delta {
+			u = u.Add(d.Mul(d))
+		}
+		u = u.Div(widths[0].Mul(widths[0]))
+	}
+	return u
+}
+
+// Record the bias node on the tape. The bias has no memory, so its outputs are constants.
+func (b Bias) Record(t *autodiff.Tape, memory []autodiff.Var, input []float64) (op []autodiff.Var, err error) {
+	return autodiff.Constants(b.Outputs), nil
+}
+
+// Record the calculation of the network on the tape, using memory in place of the network's own
+// memory. memory must be in the same order as GetMemory.
+func (nodes Network) Record(t *autodiff.Tape, memory []autodiff.Var, input []float64) (op []autodiff.Var, err error) {
+	if len(nodes) == 0 {
+		err = fmt.Errorf("rbf: Unable to calculate result for RBF network, since there are no nodes")
+		return
+	}
+	if len(memory) != nodes.GetMemorySize() {
+		err = fmt.Errorf("rbf: the network has a memory size of %d, but %d values were provided",
+			nodes.GetMemorySize(), len(memory))
+		return
+	}
+	var index int
+	for i, n := range nodes {
+		r, ok := n.(Recordable)
+		if !ok {
+			err = fmt.Errorf("rbf: node %d cannot be recorded", i)
+			return
+		}
+		var size int
+		if tn, ok := n.(Trainable); ok {
+			size = tn.GetMemorySize()
+		}
+		var nv []autodiff.Var
+		nv, err = r.Record(t, memory[index:index+size], input)
+		if err != nil {
+			return
+		}
+		index += size
+		if op == nil {
+			op = make([]autodiff.Var, len(nv))
+			for j := range op {
+				op[j] = autodiff.Constant(0)
+			}
+		}
+		if len(nv) != len(op) {
+			err = fmt.Errorf("rbf: The RBF has been configured with %d output nodes, but node %d has %d output nodes",
+				len(op), i, len(nv))
+			return
+		}
+		for j, nnv := range nv {
+			op[j] = op[j].Add(nnv)
+		}
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package rbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/autodiff"
+	"github.com/a-h/ml/distance"
+	"github.com/a-h/ml/training"
+)
+
+func TestRecordMatchesCalculate(t *testing.T) {
+	n, err := NewNetwork(NewNode(2, 2), NewNode(2, 2), NewBias(2))
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	input := []float64{0.5, -0.25}
+	expected, err := n.Calculate(input)
+	if err != nil {
+		t.Fatalf("unexpected error calculating: %v", err)
+	}
+	tape := autodiff.NewTape()
+	actual, err := n.Record(tape, tape.Variables(n.GetMemory()), input)
+	if err != nil {
+		t.Fatalf("unexpected error recording: %v", err)
+	}
+	for i := range expected {
+		if math.Abs(actual[i].Value-expected[i]) > 1e-12 {
+			t.Errorf("output %d: expected %v, got %v", i, expected[i], actual[i].Value)
+		}
+	}
+}
+
+func TestRecordGradient(t *testing.T) {
+	n, err := NewNetwork(
+		&Node{
+			Width:         1.5,
+			Centroid:      []float64{0.5, 1.0},
+			InputWeights:  []float64{1.0, 0.5},
+			OutputWeights: []float64{2.0},
+		},
+		&Node{
+			Width:         0.75,
+			Centroid:      []float64{-0.5, 0.0},
+			InputWeights:  []float64{0.5, 1.5},
+			OutputWeights: []float64{-1.0},
+		},
+		NewBias(1),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	d := []training.Data{
+		{Input: []float64{0, 0}, Expected: []float64{0}},
+		{Input: []float64{0, 1}, Expected: []float64{1}},
+		{Input: []float64{1, 0}, Expected: []float64{1}},
+		{Input: []float64{1, 1}, Expected: []float64{0}},
+	}
+	e, g, err := autodiff.Gradient(n, d, autodiff.SumOfSquares)
+	if err != nil {
+		t.Fatalf("unexpected error calculating gradient: %v", err)
+	}
+	expected, err := training.Gradient(n, d, distance.SumOfSquares)
+	if err != nil {
+		t.Fatalf("unexpected error calculating numeric gradient: %v", err)
+	}
+	for i := range expected {
+		if math.Abs(g[i]-expected[i]) > 1e-6 {
+			t.Errorf("expected gradient %v, got %v", expected, g)
+			break
+		}
+	}
+	var expectedError float64
+	for _, td := range d {
+		actual, _ := n.Calculate(td.Input)
+		de, _ := distance.SumOfSquares(actual, td.Expected)
+		expectedError += de / float64(len(d))
+	}
+	if math.Abs(e-expectedError) > 1e-12 {
+		t.Errorf("expected error %v, got %v", expectedError, e)
+	}
+}
+
+func TestRecordErrors(t *testing.T) {
+	n := &Node{
+		Width:         1,
+		Centroid:      []float64{0, 0},
+		InputWeights:  []float64{1, 1},
+		OutputWeights: []float64{1},
+	}
+	tape := autodiff.NewTape()
+	if _, err := n.Record(tape, tape.Variables([]float64{1}), []float64{0, 0}); err == nil {
+		t.Errorf("expected an error with the wrong memory size")
+	}
+	if _, err := n.Record(tape, tape.Variables(n.GetMemory()), []float64{0}); err == nil {
+		t.Errorf("expected an error with the wrong input size")
+	}
+}
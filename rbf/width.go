@@ -0,0 +1,122 @@
+package rbf
+
+// UseWidths switches the node to one width per input, each starting at the node's Width, so that
+// the output of a node with a single width is unchanged.
+func (n *Node) UseWidths() {
+	n.Covariance = nil
+	n.Widths = make([]float64, len(n.Centroid))
+	for i := range n.Widths {
+		n.Widths[i] = n.Width
+	}
+}
+
+// UseCovariance switches the node to a full covariance, starting from the node's Width or Widths,
+// so that the node's output is unchanged.
+func (n *Node) UseCovariance() {
+	if len(n.Covariance) > 0 {
+		return
+	}
+	n.Covariance = make([]float64, triangleSize(len(n.Centroid)))
+	for i := range n.Centroid {
+		w := n.Width
+		if len(n.Widths) > 0 {
+			w = n.Widths[i]
+		}
+		n.Covariance[triangleIndex(i, i)] = w
+	}
+	n.Widths = nil
+}
+
+// triangleSize returns the number of values in the lower triangle of a d by d matrix.
+func triangleSize(d int) int {
+	return d * (d + 1) / 2
+}
+
+// triangleIndex returns the index of row i, column j (where j <= i) of a packed lower triangle.
+func triangleIndex(i, j int) int {
+	return i*(i+1)/2 + j
+}
+
+// distance returns u, the squared distance scaled by the node's width. For a single width this
+// is |δ|² / w², for per-input widths Σ(δᵢ / wᵢ)², and for a covariance LLᵀ it is |y|², where
+// Ly = δ. When a Covariance is used, delta is overwritten with y.
+func (n *Node) distance(delta []float64) (u float64) {
+	switch {
+	case len(n.Covariance) > 0:
+		for _, y := range n.forwardSubstitute(delta, delta) {
+			u += y * y
+		}
+	case len(n.Widths) > 0:
+		for i, d := range delta {
+			u += (d / n.Widths[i]) * (d / n.Widths[i])
+		}
+	default:
+		for _, d := range delta {
+			u += d * d
+		}
+		u /= n.Width * n.Width
+	}
+	return
+}
+
+// distanceGradient returns u, along with its partial derivatives with respect to each value of
+// delta and each width parameter, in the same order as the width parameters appear in memory.
+func (n *Node) distanceGradient(delta []float64) (u float64, dDelta, dWidth []float64) {
+	dDelta = make([]float64, len(delta))
+	dWidth = make([]float64, n.widthSize())
+	switch {
+	case len(n.Covariance) > 0:
+		// u = yᵀy, where y = L⁻¹δ, so du/dδ = 2z and du/dL = -2zyᵀ, where z = L⁻ᵀy.
+		y := n.forwardSubstitute(delta, make([]float64, len(delta)))
+		z := n.backSubstitute(y)
+		for i := range delta {
+			u += y[i] * y[i]
+			dDelta[i] = 2 * z[i]
+			for j := 0; j <= i; j++ {
+				dWidth[triangleIndex(i, j)] = -2 * z[i] * y[j]
+			}
+		}
+	case len(n.Widths) > 0:
+		for i, d := range delta {
+			w2 := n.Widths[i] * n.Widths[i]
+			u += d * d / w2
+			dDelta[i] = 2 * d / w2
+			dWidth[i] = -2 * d * d / (w2 * n.Widths[i])
+		}
+	default:
+		w2 := n.Width * n.Width
+		for i, d := range delta {
+			u += d * d
+			dDelta[i] = 2 * d / w2
+		}
+		u /= w2
+		dWidth[0] = -2 * u / n.Width
+	}
+	return
+}
+
+// forwardSubstitute solves Ly = v, where L is the node's Covariance, writing the result to y,
+// which may be v.
+func (n *Node) forwardSubstitute(v, y []float64) []float64 {
+	for i := range v {
+		sum := v[i]
+		for j := 0; j < i; j++ {
+			sum -= n.Covariance[triangleIndex(i, j)] * y[j]
+		}
+		y[i] = sum / n.Covariance[triangleIndex(i, i)]
+	}
+	return y
+}
+
+// backSubstitute solves Lᵀz = v, where L is the node's Covariance.
+func (n *Node) backSubstitute(v []float64) (z []float64) {
+	z = make([]float64, len(v))
+	for i := len(v) - 1; i >= 0; i-- {
+		sum := v[i]
+		for j := i + 1; j < len(v); j++ {
+			sum -= n.Covariance[triangleIndex(j, i)] * z[j]
+		}
+		z[i] = sum / n.Covariance[triangleIndex(i, i)]
+	}
+	return
+}
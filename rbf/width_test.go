@@ -0,0 +1,242 @@
+package rbf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/a-h/ml/autodiff"
+	"github.com/a-h/ml/distance"
+	"github.com/a-h/ml/training"
+)
+
+func TestWidthMemory(t *testing.T) {
+	tests := []struct {
+		name         string
+		node         *Node
+		expectedSize int
+	}{
+		{
+			name: "single width",
+			node: &Node{
+				Width:         0.5,
+				Centroid:      []float64{1, 2},
+				InputWeights:  []float64{3, 4},
+				OutputWeights: []float64{5},
+			},
+			expectedSize: 4,
+		},
+		{
+			name: "trainable centroid",
+			node: &Node{
+				TrainCentroid: true,
+				Width:         0.5,
+				Centroid:      []float64{1, 2},
+				InputWeights:  []float64{3, 4},
+				OutputWeights: []float64{5},
+			},
+			expectedSize: 6,
+		},
+		{
+			name: "per-input widths",
+			node: &Node{
+				Widths:        []float64{0.5, 0.25},
+				Centroid:      []float64{1, 2},
+				InputWeights:  []float64{3, 4},
+				OutputWeights: []float64{5},
+			},
+			expectedSize: 5,
+		},
+		{
+			name: "trainable centroid with covariance",
+			node: &Node{
+				TrainCentroid: true,
+				Covariance:    []float64{0.5, 0.1, 0.25},
+				Centroid:      []float64{1, 2},
+				InputWeights:  []float64{3, 4},
+				OutputWeights: []float64{5},
+			},
+			expectedSize: 8,
+		},
+	}
+
+	for _, test := range tests {
+		memory := test.node.GetMemory()
+		if len(memory) != test.expectedSize || test.node.GetMemorySize() != test.expectedSize {
+			t.Errorf("%s: expected memory size %d, got %d values and a size of %d", test.name,
+				test.expectedSize, len(memory), test.node.GetMemorySize())
+		}
+		for i := range memory {
+			memory[i] = float64(i)
+		}
+		test.node.SetMemory(memory)
+		if actual := test.node.GetMemory(); !reflect.DeepEqual(actual, memory) {
+			t.Errorf("%s: expected memory %v after setting it, got %v", test.name, memory, actual)
+		}
+	}
+}
+
+func TestUseWidthsAndCovariance(t *testing.T) {
+	n := &Node{
+		Width:         1.5,
+		Centroid:      []float64{0.5, 1.0},
+		InputWeights:  []float64{1.0, 0.5},
+		OutputWeights: []float64{2.0},
+	}
+	input := []float64{0.25, -0.5}
+	expected, err := n.Calculate(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	n.UseWidths()
+	actual, err := n.Calculate(input)
+	if err != nil {
+		t.Fatalf("widths: unexpected error: %v", err)
+	}
+	if math.Abs(actual[0]-expected[0]) > 1e-12 {
+		t.Errorf("widths: expected %v, got %v", expected, actual)
+	}
+
+	n.Widths[1] = 3
+	expected, err = n.Calculate(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n.UseCovariance()
+	if !reflect.DeepEqual(n.Covariance, []float64{1.5, 0, 3}) || n.Widths != nil {
+		t.Errorf("covariance: expected the widths to become the diagonal, got %v", n.Covariance)
+	}
+	actual, err = n.Calculate(input)
+	if err != nil {
+		t.Fatalf("covariance: unexpected error: %v", err)
+	}
+	if math.Abs(actual[0]-expected[0]) > 1e-12 {
+		t.Errorf("covariance: expected %v, got %v", expected, actual)
+	}
+}
+
+func TestCovarianceDistance(t *testing.T) {
+	// L = [[2, 0], [1, 1]], so LLᵀ = [[4, 2], [2, 2]] and (LLᵀ)⁻¹ = [[0.5, -0.5], [-0.5, 1]].
+	n := &Node{
+		Covariance:    []float64{2, 1, 1},
+		Centroid:      []float64{1, 1},
+		InputWeights:  []float64{1, 1},
+		OutputWeights: []float64{1},
+	}
+	// δ = [2, 1], so u = δᵀ(LLᵀ)⁻¹δ = 2 - 2 + 1 = 1.
+	actual, err := n.Calculate([]float64{3, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := math.Exp(-0.5)
+	if math.Abs(actual[0]-expected) > 1e-12 {
+		t.Errorf("expected %v, got %v", expected, actual[0])
+	}
+}
+
+func TestWidthGradients(t *testing.T) {
+	tests := []struct {
+		name string
+		node *Node
+	}{
+		{
+			name: "trainable centroid",
+			node: &Node{
+				TrainCentroid: true,
+				Width:         1.5,
+				Centroid:      []float64{0.5, 1.0},
+				InputWeights:  []float64{1.0, 0.5},
+				OutputWeights: []float64{2.0, -0.5},
+			},
+		},
+		{
+			name: "per-input widths",
+			node: &Node{
+				Kernel:        KernelInverseMultiquadric,
+				Widths:        []float64{1.5, 0.75},
+				Centroid:      []float64{0.5, 1.0},
+				InputWeights:  []float64{1.0, 0.5},
+				OutputWeights: []float64{2.0, -0.5},
+			},
+		},
+		{
+			name: "covariance",
+			node: &Node{
+				TrainCentroid: true,
+				Covariance:    []float64{1.5, 0.5, 0.75},
+				Centroid:      []float64{0.5, 1.0},
+				InputWeights:  []float64{1.0, 0.5},
+				OutputWeights: []float64{2.0, -0.5},
+			},
+		},
+	}
+	d := []training.Data{
+		{Input: []float64{0, 0}, Expected: []float64{0, 1}},
+		{Input: []float64{0, 1}, Expected: []float64{1, 0}},
+		{Input: []float64{1, 0}, Expected: []float64{1, 0}},
+		{Input: []float64{1, 1}, Expected: []float64{0, 1}},
+	}
+
+	for _, test := range tests {
+		n, err := NewNetwork(test.node, NewBias(2))
+		if err != nil {
+			t.Fatalf("%s: unexpected error creating network: %v", test.name, err)
+		}
+		expected, err := training.Gradient(n, d, distance.SumOfSquares)
+		if err != nil {
+			t.Fatalf("%s: unexpected error calculating numeric gradient: %v", test.name, err)
+		}
+		_, backpropagated, err := training.Differentiate(n, d, distance.SumOfSquares)()
+		if err != nil {
+			t.Fatalf("%s: unexpected error backpropagating: %v", test.name, err)
+		}
+		_, recorded, err := autodiff.Gradient(n, d, autodiff.SumOfSquares)
+		if err != nil {
+			t.Fatalf("%s: unexpected error recording: %v", test.name, err)
+		}
+		if len(backpropagated) != len(expected) || len(recorded) != len(expected) {
+			t.Fatalf("%s: expected %d values, got %d (backpropagated) and %d (recorded)", test.name,
+				len(expected), len(backpropagated), len(recorded))
+		}
+		for i := range expected {
+			if math.Abs(backpropagated[i]-expected[i]) > 1e-6 || math.Abs(recorded[i]-expected[i]) > 1e-6 {
+				t.Errorf("%s: expected gradient %v, got %v (backpropagated) and %v (recorded)",
+					test.name, expected, backpropagated, recorded)
+				break
+			}
+		}
+	}
+}
+
+func TestWidthErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		node *Node
+	}{
+		{
+			name: "too few widths",
+			node: &Node{
+				Widths:        []float64{1},
+				Centroid:      []float64{0, 0},
+				InputWeights:  []float64{1, 1},
+				OutputWeights: []float64{1},
+			},
+		},
+		{
+			name: "covariance of the wrong size",
+			node: &Node{
+				Covariance:    []float64{1, 0},
+				Centroid:      []float64{0, 0},
+				InputWeights:  []float64{1, 1},
+				OutputWeights: []float64{1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := test.node.Calculate([]float64{1, 1}); err == nil {
+			t.Errorf("%s: expected an error, got nil", test.name)
+		}
+	}
+}
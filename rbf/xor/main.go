@@ -67,7 +67,10 @@ func main() {
 		os.Exit(-1)
 	}
 
-	network.SetMemory(algorithm.BestMemory())
+	if err = network.SetMemory(algorithm.BestMemory()); err != nil {
+		fmt.Println("Error setting memory:", err)
+		os.Exit(-1)
+	}
 	fmt.Println("Time:", time.Now().Sub(start))
 	fmt.Println("Iterations:", iterations)
 	fmt.Println("Output error:", algorithm.BestError())
@@ -0,0 +1,177 @@
+package training
+
+import (
+	"math"
+
+	"github.com/a-h/ml/random"
+)
+
+// A Schedule returns the temperature for an iteration of simulated annealing, given whether the
+// move made in the previous iteration was accepted. The iteration starts from zero, and returns
+// to zero when the temperature is reheated. Schedules may keep state between calls, so a Schedule
+// must not be shared between SimulatedAnnealing instances.
+type Schedule func(iteration int, accepted bool) (temperature float64)
+
+// ExponentialSchedule multiplies the initial temperature by rate at each iteration.
+func ExponentialSchedule(initial, rate float64) Schedule {
+	return func(iteration int, accepted bool) float64 {
+		return initial * math.Pow(rate, float64(iteration))
+	}
+}
+
+// LinearSchedule reduces the temperature from initial to zero over the number of iterations. If
+// iterations isn't positive, the temperature is always zero.
+func LinearSchedule(initial float64, iterations int) Schedule {
+	return func(iteration int, accepted bool) float64 {
+		if iterations <= 0 {
+			return 0
+		}
+		return initial * math.Max(0, 1-float64(iteration)/float64(iterations))
+	}
+}
+
+// LogarithmicSchedule divides the initial temperature by ln(iteration + e), which cools slowly
+// enough to guarantee finding the global minimum, given unlimited iterations.
+func LogarithmicSchedule(initial float64) Schedule {
+	return func(iteration int, accepted bool) float64 {
+		return initial / math.Log(float64(iteration)+math.E)
+	}
+}
+
+// AdaptiveSchedule measures the proportion of moves accepted over each window of iterations. The
+// temperature is multiplied by fast while more than target of the moves are accepted, and by slow
+// once fewer are, so that the search cools quickly until it starts to settle.
+//
+// The acceptance rate and temperature are held by the returned Schedule, and reset at iteration
+// zero, so each SimulatedAnnealing instance needs its own AdaptiveSchedule.
+func AdaptiveSchedule(initial, target float64, window int, fast, slow float64) Schedule {
+	temperature := initial
+	var accepts, moves int
+	return func(iteration int, accepted bool) float64 {
+		if iteration == 0 {
+			temperature, accepts, moves = initial, 0, 0
+			return temperature
+		}
+		moves++
+		if accepted {
+			accepts++
+		}
+		if moves >= window {
+			if float64(accepts)/float64(moves) > target {
+				temperature *= fast
+			} else {
+				temperature *= slow
+			}
+			accepts, moves = 0, 0
+		}
+		return temperature
+	}
+}
+
+// NewSimulatedAnnealing creates simulated annealing training, which starts at the memory and
+// cools according to the schedule.
+func NewSimulatedAnnealing(memory []float64, schedule Schedule) *SimulatedAnnealing {
+	min, max := -10.0, 10.0
+	return &SimulatedAnnealing{
+		Min:       min,
+		Max:       max,
+		Schedule:  schedule,
+		StepScale: 0.01,
+		current:   memory,
+		e:         math.MaxFloat64,
+	}
+}
+
+// SimulatedAnnealing is a training algorithm which moves to a random neighbour of the current
+// state, always accepting moves which reduce the error, and accepting moves which increase the
+// error by d with a probability of exp(-d / temperature). As the temperature falls, the search
+// settles into a minimum.
+//
+// Each call to Next evaluates a single neighbour. The best memory found is recorded separately
+// from the current state, which may have a higher error.
+type SimulatedAnnealing struct {
+	// current is the neighbour being evaluated.
+	current []float64
+	// best memory and error recorded during training.
+	best []float64
+	e    float64
+	// Min and Max values for memory values.
+	Min, Max float64
+	// Schedule sets the temperature at each iteration.
+	Schedule Schedule
+	// StepScale is the standard deviation of the Gaussian noise added to each value of the state
+	// to choose a neighbour, as a fraction of Max - Min.
+	StepScale float64
+	// ReheatAfter restarts the Schedule from iteration zero if the best error hasn't improved for
+	// this many iterations. If zero, the temperature is never reheated.
+	ReheatAfter int
+	// Reheats is the number of times the temperature has been reheated.
+	Reheats int
+	// Temperature is the current temperature.
+	Temperature float64
+
+	state      []float64
+	stateError float64
+	iteration  int
+	stale      int
+}
+
+// Next records the error of the previous neighbour, decides whether to move to it, and returns
+// the next neighbour to evaluate.
+func (sa *SimulatedAnnealing) Next(ev Evaluator) ([]float64, error) {
+	e, err := ev()
+	if err != nil {
+		return sa.current, err
+	}
+	if math.IsNaN(e) {
+		e = math.Inf(1)
+	}
+
+	var accepted bool
+	if sa.state == nil {
+		sa.Temperature = sa.Schedule(0, true)
+		accepted = true
+	} else {
+		accepted = e <= sa.stateError ||
+			(sa.Temperature > 0 && random.Float64(0, 1) < math.Exp((sa.stateError-e)/sa.Temperature))
+	}
+	if accepted {
+		sa.state = append(sa.state[:0], sa.current...)
+		sa.stateError = e
+	}
+
+	sa.stale++
+	if e < sa.e {
+		sa.best = append(sa.best[:0], sa.current...)
+		sa.e = e
+		sa.stale = 0
+	}
+
+	sa.iteration++
+	if sa.ReheatAfter > 0 && sa.stale >= sa.ReheatAfter {
+		sa.iteration = 0
+		sa.stale = 0
+		sa.Reheats++
+	}
+	sa.Temperature = sa.Schedule(sa.iteration, accepted)
+
+	scale := sa.StepScale * (sa.Max - sa.Min)
+	sa.current = make([]float64, len(sa.state))
+	for i, v := range sa.state {
+		sa.current[i] = v + random.Normal(0, scale)
+	}
+	clamp(sa.current, sa.Min, sa.Max)
+	return sa.current, nil
+}
+
+// BestError returns the best (lowest) error discovered by training.
+// If no training has happened, it will math.MaxFloat64.
+func (sa *SimulatedAnnealing) BestError() (e float64) {
+	return sa.e
+}
+
+// BestMemory returns the best set of parameters discovered by the algorithm during training.
+// If no training has happened, it will return nil.
+func (sa *SimulatedAnnealing) BestMemory() (memory []float64) {
+	return sa.best
+}
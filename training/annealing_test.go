@@ -0,0 +1,133 @@
+package training
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+)
+
+func TestSchedules(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedule  Schedule
+		iteration int
+		expected  float64
+	}{
+		{name: "exponential start", schedule: ExponentialSchedule(10, 0.5), iteration: 0, expected: 10},
+		{name: "exponential", schedule: ExponentialSchedule(10, 0.5), iteration: 3, expected: 1.25},
+		{name: "linear start", schedule: LinearSchedule(10, 100), iteration: 0, expected: 10},
+		{name: "linear", schedule: LinearSchedule(10, 100), iteration: 25, expected: 7.5},
+		{name: "linear end", schedule: LinearSchedule(10, 100), iteration: 200, expected: 0},
+		{name: "linear without iterations", schedule: LinearSchedule(10, 0), iteration: 0, expected: 0},
+		{name: "logarithmic start", schedule: LogarithmicSchedule(10), iteration: 0, expected: 10},
+		{name: "logarithmic", schedule: LogarithmicSchedule(10), iteration: 10, expected: 10 / math.Log(10+math.E)},
+	}
+
+	for _, test := range tests {
+		actual := test.schedule(test.iteration, true)
+		if math.IsNaN(actual) || math.Abs(actual-test.expected) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestAdaptiveSchedule(t *testing.T) {
+	s := AdaptiveSchedule(10, 0.5, 4, 0.5, 0.9)
+	if actual := s(0, true); actual != 10 {
+		t.Errorf("expected an initial temperature of 10, got %v", actual)
+	}
+	// 3 of 4 moves accepted cools quickly.
+	for i, accepted := range []bool{true, true, false, true} {
+		s(i+1, accepted)
+	}
+	if actual := s(5, true); actual != 5 {
+		t.Errorf("expected a temperature of 5 after accepting most moves, got %v", actual)
+	}
+	// 1 of 4 moves accepted cools slowly.
+	for i, accepted := range []bool{false, false, false} {
+		s(i+6, accepted)
+	}
+	if actual := s(9, false); actual != 4.5 {
+		t.Errorf("expected a temperature of 4.5 after rejecting most moves, got %v", actual)
+	}
+	// Reheating resets the temperature.
+	if actual := s(0, false); actual != 10 {
+		t.Errorf("expected a temperature of 10 after reheating, got %v", actual)
+	}
+}
+
+func TestSimulatedAnnealing(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+	}{
+		{name: "exponential", schedule: ExponentialSchedule(10, 0.999)},
+		{name: "linear", schedule: LinearSchedule(10, 10000)},
+		{name: "logarithmic", schedule: LogarithmicSchedule(1)},
+		{name: "adaptive", schedule: AdaptiveSchedule(10, 0.2, 100, 0.8, 0.98)},
+	}
+
+	for _, test := range tests {
+		trainee := &lineTrainee{memory: []float64{0, 0}}
+		sa := NewSimulatedAnnealing(trainee.GetMemory(), test.schedule)
+		_, err := Complete(trainee, lineData, sa, distance.SumOfSquares, StopAfterXIterations(10000))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if sa.BestError() > 0.1 {
+			t.Errorf("%s: expected an error below 0.1, got %v with memory %v", test.name, sa.BestError(), sa.BestMemory())
+		}
+	}
+}
+
+func TestSimulatedAnnealingTracksBest(t *testing.T) {
+	// A very high temperature accepts almost every move, so the state wanders away from the best.
+	sa := NewSimulatedAnnealing([]float64{0}, ExponentialSchedule(1e9, 1))
+	memory := sa.current
+	lowest := math.MaxFloat64
+	ev := func() (float64, error) {
+		e := memory[0] * memory[0]
+		lowest = math.Min(lowest, e)
+		return e, nil
+	}
+	for i := 0; i < 1000; i++ {
+		var err error
+		if memory, err = sa.Next(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, v := range memory {
+			if v < sa.Min || v > sa.Max {
+				t.Fatalf("expected memory between Min and Max, got %v", memory)
+			}
+		}
+	}
+	if sa.BestError() != 0 || sa.BestMemory()[0] != 0 {
+		t.Errorf("expected the starting memory to be the best, got %v with error %v", sa.BestMemory(), sa.BestError())
+	}
+	if sa.stateError == 0 {
+		t.Errorf("expected the state to have moved away from the best")
+	}
+}
+
+func TestSimulatedAnnealingReheats(t *testing.T) {
+	sa := NewSimulatedAnnealing([]float64{0}, ExponentialSchedule(10, 0.5))
+	sa.ReheatAfter = 10
+	ev := func() (float64, error) {
+		return 1, nil
+	}
+	for i := 0; i < 25; i++ {
+		if _, err := sa.Next(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if sa.Reheats != 2 {
+		t.Errorf("expected 2 reheats, got %d", sa.Reheats)
+	}
+	// The first call sets the best error, and reheats happen on calls 11 and 21, leaving 4
+	// iterations since the last reheat.
+	if sa.Temperature != 10*math.Pow(0.5, 4) {
+		t.Errorf("expected a temperature of %v, got %v", 10*math.Pow(0.5, 4), sa.Temperature)
+	}
+}
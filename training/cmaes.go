@@ -0,0 +1,374 @@
+package training
+
+import (
+	"math"
+
+	"github.com/a-h/ml/random"
+)
+
+// NewCMAES creates CMA-ES training, with a search distribution centred on the memory.
+func NewCMAES(memory []float64) *CMAES {
+	min, max := -10.0, 10.0
+	populationSize := 4
+	if len(memory) > 0 {
+		populationSize += int(3 * math.Log(float64(len(memory))))
+	}
+	return &CMAES{
+		Min:                min,
+		Max:                max,
+		PopulationSize:     populationSize,
+		Sigma:              0.3,
+		PopulationIncrease: 2,
+		Tolerance:          1e-12,
+		ErrorTolerance:     1e-12,
+		current:            memory,
+		e:                  math.MaxFloat64,
+	}
+}
+
+// CMAES is the Covariance Matrix Adaptation Evolution Strategy. Each generation, it samples a
+// population from a multivariate normal distribution, and moves the mean of the distribution
+// towards the members with the lowest error. The covariance matrix and step size of the
+// distribution are adapted to the path the mean has taken, so that the search learns the scale
+// and correlations of the memory values.
+//
+// When the search stops making progress, it restarts from a random point between Min and Max
+// with a larger population (IPOP-CMA-ES), which makes a global minimum more likely to be found.
+//
+// Each call to Next evaluates a single member of the population.
+type CMAES struct {
+	current []float64
+	// best memory and error recorded during training.
+	best []float64
+	e    float64
+	// Min and Max values for memory values.
+	Min, Max float64
+	// PopulationSize is the number of samples taken in each generation of the first run.
+	PopulationSize int
+	// Sigma is the initial step size, as a fraction of Max - Min.
+	Sigma float64
+	// PopulationIncrease multiplies the population size at each restart.
+	PopulationIncrease float64
+	// Tolerance restarts the search once the standard deviation of the distribution is less than
+	// Tolerance times its initial value in every dimension.
+	Tolerance float64
+	// ErrorTolerance restarts the search once the range of errors in a generation is less than
+	// ErrorTolerance.
+	ErrorTolerance float64
+	// Generation is the number of generations since training started.
+	Generation int
+	// Restarts is the number of times the search has restarted.
+	Restarts int
+
+	state   *cmaesState
+	samples [][]float64
+	errors  []float64
+	index   int
+}
+
+// cmaesState is the search distribution of a single run.
+type cmaesState struct {
+	lambda, mu            int
+	weights               []float64
+	mueff                 float64
+	cc, cs, c1, cmu, damp float64
+	chiN                  float64
+
+	mean   []float64
+	sigma  float64
+	sigma0 float64
+	// c is the covariance matrix, which is b * diag(d²) * bᵀ.
+	c, b [][]float64
+	d    []float64
+	// pc and ps are the evolution paths of c and sigma.
+	pc, ps      []float64
+	evaluations int
+}
+
+func newCMAESState(mean []float64, sigma float64, lambda int) *cmaesState {
+	n := float64(len(mean))
+	s := &cmaesState{
+		lambda: lambda,
+		mu:     lambda / 2,
+		mean:   mean,
+		sigma:  sigma,
+		sigma0: sigma,
+		c:      identity(len(mean)),
+		b:      identity(len(mean)),
+		d:      make([]float64, len(mean)),
+		pc:     make([]float64, len(mean)),
+		ps:     make([]float64, len(mean)),
+	}
+	for i := range s.d {
+		s.d[i] = 1
+	}
+	s.weights = make([]float64, s.mu)
+	var total, squares float64
+	for i := range s.weights {
+		s.weights[i] = math.Log(float64(s.mu)+0.5) - math.Log(float64(i+1))
+		total += s.weights[i]
+	}
+	for i := range s.weights {
+		s.weights[i] /= total
+		squares += s.weights[i] * s.weights[i]
+	}
+	s.mueff = 1 / squares
+	s.cc = (4 + s.mueff/n) / (n + 4 + 2*s.mueff/n)
+	s.cs = (s.mueff + 2) / (n + s.mueff + 5)
+	s.c1 = 2 / ((n+1.3)*(n+1.3) + s.mueff)
+	s.cmu = math.Min(1-s.c1, 2*(s.mueff-2+1/s.mueff)/((n+2)*(n+2)+s.mueff))
+	s.damp = 1 + 2*math.Max(0, math.Sqrt((s.mueff-1)/(n+1))-1) + s.cs
+	s.chiN = math.Sqrt(n) * (1 - 1/(4*n) + 1/(21*n*n))
+	return s
+}
+
+// Next records the error of the previous member of the population, and returns the next member
+// to evaluate.
+func (cma *CMAES) Next(ev Evaluator) ([]float64, error) {
+	e, err := ev()
+	if err != nil {
+		return cma.current, err
+	}
+	if math.IsNaN(e) {
+		e = math.Inf(1)
+	}
+	if e < cma.e {
+		cma.best = append(cma.best[:0], cma.current...)
+		cma.e = e
+	}
+	// There's nothing to search if the memory is empty.
+	if len(cma.current) == 0 {
+		return cma.current, nil
+	}
+	if cma.state == nil {
+		// The initial memory isn't part of a generation.
+		cma.start(append([]float64(nil), cma.current...), cma.PopulationSize)
+	} else {
+		cma.errors[cma.index] = e
+		cma.index++
+		if cma.index == len(cma.samples) {
+			cma.update()
+		}
+	}
+	cma.current = cma.samples[cma.index]
+	return cma.current, nil
+}
+
+// start a new run from the mean, and sample the first generation.
+func (cma *CMAES) start(mean []float64, lambda int) {
+	if lambda < 2 {
+		lambda = 2
+	}
+	cma.state = newCMAESState(mean, cma.Sigma*(cma.Max-cma.Min), lambda)
+	cma.sample()
+}
+
+// sample a generation from the distribution.
+func (cma *CMAES) sample() {
+	s := cma.state
+	cma.samples = make([][]float64, s.lambda)
+	cma.errors = make([]float64, s.lambda)
+	for k := range cma.samples {
+		z := make([]float64, len(s.mean))
+		for i := range z {
+			z[i] = s.d[i] * random.Normal(0, 1)
+		}
+		x := make([]float64, len(s.mean))
+		for i := range x {
+			var y float64
+			for j := range z {
+				y += s.b[i][j] * z[j]
+			}
+			x[i] = s.mean[i] + s.sigma*y
+		}
+		clamp(x, cma.Min, cma.Max)
+		cma.samples[k] = x
+	}
+	cma.index = 0
+}
+
+// update the distribution from the errors of the generation, and sample the next generation,
+// restarting if the search has stopped making progress.
+func (cma *CMAES) update() {
+	s := cma.state
+	n := len(s.mean)
+	order := rank(cma.errors)
+	s.evaluations += s.lambda
+	cma.Generation++
+
+	// The steps taken by the selected samples, in units of sigma. Samples are clamped between Min
+	// and Max, so the steps are calculated from the samples, rather than stored.
+	y := make([][]float64, s.mu)
+	mean := make([]float64, n)
+	for k := range y {
+		y[k] = make([]float64, n)
+		for i := range y[k] {
+			y[k][i] = (cma.samples[order[k]][i] - s.mean[i]) / s.sigma
+			mean[i] += s.weights[k] * cma.samples[order[k]][i]
+		}
+	}
+	step := make([]float64, n)
+	for i := range step {
+		step[i] = (mean[i] - s.mean[i]) / s.sigma
+	}
+	s.mean = mean
+
+	// ps is updated with C^-½ * step, where C^-½ = b * diag(1/d) * bᵀ.
+	bt := make([]float64, n)
+	for j := range bt {
+		for i := range step {
+			bt[j] += s.b[i][j] * step[i]
+		}
+		bt[j] /= s.d[j]
+	}
+	psScale := math.Sqrt(s.cs * (2 - s.cs) * s.mueff)
+	var psNorm float64
+	for i := range s.ps {
+		var v float64
+		for j := range bt {
+			v += s.b[i][j] * bt[j]
+		}
+		s.ps[i] = (1-s.cs)*s.ps[i] + psScale*v
+		psNorm += s.ps[i] * s.ps[i]
+	}
+	psNorm = math.Sqrt(psNorm)
+
+	var hsig float64
+	if psNorm/math.Sqrt(1-math.Pow(1-s.cs, 2*float64(s.evaluations)/float64(s.lambda)))/s.chiN < 1.4+2/float64(n+1) {
+		hsig = 1
+	}
+	pcScale := math.Sqrt(s.cc * (2 - s.cc) * s.mueff)
+	for i := range s.pc {
+		s.pc[i] = (1-s.cc)*s.pc[i] + hsig*pcScale*step[i]
+	}
+
+	for i := range s.c {
+		for j := 0; j <= i; j++ {
+			var rankMu float64
+			for k := range y {
+				rankMu += s.weights[k] * y[k][i] * y[k][j]
+			}
+			v := (1-s.c1-s.cmu)*s.c[i][j] +
+				s.c1*(s.pc[i]*s.pc[j]+(1-hsig)*s.cc*(2-s.cc)*s.c[i][j]) +
+				s.cmu*rankMu
+			s.c[i][j], s.c[j][i] = v, v
+		}
+	}
+	s.sigma *= math.Exp((s.cs / s.damp) * (psNorm/s.chiN - 1))
+
+	var values []float64
+	values, s.b = eigen(s.c)
+	for i, v := range values {
+		s.d[i] = math.Sqrt(math.Max(v, 1e-300))
+	}
+
+	if cma.stopped(order) {
+		cma.Restarts++
+		lambda := int(float64(s.lambda) * cma.PopulationIncrease)
+		cma.start(random.Float64Vector(cma.Min, cma.Max, n), lambda)
+		return
+	}
+	cma.sample()
+}
+
+// stopped returns true if the current run has converged, or can no longer make progress.
+func (cma *CMAES) stopped(order []int) bool {
+	s := cma.state
+	if math.IsNaN(s.sigma) || math.IsInf(s.sigma, 0) {
+		return true
+	}
+	if cma.errors[order[len(order)-1]]-cma.errors[order[0]] < cma.ErrorTolerance {
+		return true
+	}
+	converged := true
+	for i := range s.c {
+		if s.sigma*math.Sqrt(s.c[i][i]) >= cma.Tolerance*s.sigma0 ||
+			math.Abs(s.sigma*s.pc[i]) >= cma.Tolerance*s.sigma0 {
+			converged = false
+			break
+		}
+	}
+	if converged {
+		return true
+	}
+	// Restart if the covariance matrix is too badly conditioned to sample from.
+	dmin, dmax := math.Inf(1), 0.0
+	for _, d := range s.d {
+		dmin, dmax = math.Min(dmin, d), math.Max(dmax, d)
+	}
+	return dmax/dmin > 1e7
+}
+
+func identity(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		m[i][i] = 1
+	}
+	return m
+}
+
+// eigen returns the eigenvalues and eigenvectors (as the columns of vectors) of the symmetric
+// matrix a, using the cyclic Jacobi method.
+func eigen(a [][]float64) (values []float64, vectors [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = append([]float64(nil), a[i]...)
+	}
+	vectors = identity(n)
+	for sweep := 0; sweep < 50; sweep++ {
+		var off float64
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vectors[k][p], vectors[k][q]
+					vectors[k][p], vectors[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+	values = make([]float64, n)
+	for i := range values {
+		values[i] = m[i][i]
+	}
+	return values, vectors
+}
+
+// BestError returns the best (lowest) error discovered by training.
+// If no training has happened, it will math.MaxFloat64.
+func (cma *CMAES) BestError() (e float64) {
+	return cma.e
+}
+
+// BestMemory returns the best set of parameters discovered by the algorithm during training.
+// If no training has happened, it will return nil.
+func (cma *CMAES) BestMemory() (memory []float64) {
+	return cma.best
+}
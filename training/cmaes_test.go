@@ -0,0 +1,114 @@
+package training
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+)
+
+func TestCMAES(t *testing.T) {
+	trainee := &lineTrainee{memory: []float64{0, 0}}
+	cma := NewCMAES(trainee.GetMemory())
+	_, err := Complete(trainee, lineData, cma, distance.SumOfSquares, StopAfterXIterations(1000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cma.BestError() > 1e-6 {
+		t.Errorf("expected an error below 1e-6, got %v with memory %v", cma.BestError(), cma.BestMemory())
+	}
+	if cma.PopulationSize != 6 {
+		t.Errorf("expected a default population size of 6, got %d", cma.PopulationSize)
+	}
+}
+
+func TestCMAESRosenbrock(t *testing.T) {
+	cma := NewCMAES([]float64{-1.2, 1})
+	cma.Min, cma.Max = -5, 5
+	memory := cma.current
+	ev := func() (float64, error) {
+		return rosenbrock(memory), nil
+	}
+	var err error
+	for i := 0; i < 5000; i++ {
+		if memory, err = cma.Next(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if cma.BestError() > 1e-8 {
+		t.Errorf("expected an error below 1e-8, got %v with memory %v", cma.BestError(), cma.BestMemory())
+	}
+}
+
+func TestCMAESRestarts(t *testing.T) {
+	cma := NewCMAES([]float64{1, 2, 3})
+	memory := cma.current
+	ev := func() (float64, error) {
+		var e float64
+		for _, v := range memory {
+			e += v * v
+		}
+		return e, nil
+	}
+	var err error
+	for i := 0; i < 100000 && cma.Restarts < 2; i++ {
+		if memory, err = cma.Next(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if cma.Restarts < 2 {
+		t.Fatalf("expected the search to restart once it converged, after %d generations", cma.Generation)
+	}
+	if expected := cma.PopulationSize * 4; cma.state.lambda != expected || len(cma.samples) != expected {
+		t.Errorf("expected the population to double at each restart to %d, got %d", expected, cma.state.lambda)
+	}
+	if cma.BestError() > 1e-12 {
+		t.Errorf("expected an error below 1e-12, got %v with memory %v", cma.BestError(), cma.BestMemory())
+	}
+}
+
+func TestCMAESWithoutMemory(t *testing.T) {
+	a := NewCMAES([]float64{})
+	ev := func() (float64, error) {
+		return 1, nil
+	}
+	for i := 0; i < 10; i++ {
+		memory, err := a.Next(ev)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(memory) != 0 {
+			t.Fatalf("expected empty memory, got %v", memory)
+		}
+	}
+	if a.BestError() != 1 {
+		t.Errorf("expected a best error of 1, got %v", a.BestError())
+	}
+}
+
+func TestEigen(t *testing.T) {
+	tests := []struct {
+		name string
+		a    [][]float64
+	}{
+		{name: "diagonal", a: [][]float64{{2, 0}, {0, 3}}},
+		{name: "symmetric", a: [][]float64{{2, 1}, {1, 2}}},
+		{name: "3x3", a: [][]float64{{4, 1, -2}, {1, 2, 0}, {-2, 0, 3}}},
+	}
+
+	for _, test := range tests {
+		values, vectors := eigen(test.a)
+		// a * v = λ * v for each eigenvector v.
+		for k, value := range values {
+			for i := range test.a {
+				var av float64
+				for j := range test.a {
+					av += test.a[i][j] * vectors[j][k]
+				}
+				if math.Abs(av-value*vectors[i][k]) > 1e-9 {
+					t.Errorf("%s: eigenvector %d: expected %v, got %v", test.name, k, value*vectors[i][k], av)
+				}
+			}
+		}
+	}
+}
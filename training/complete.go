@@ -11,16 +11,35 @@ import (
 // the distance function dist to calculate the distance between the expected and received values.
 // Stoppers can be provided to limit the training to a time period, maximum number of iterations,
 // error etc.
+// If the algorithm is a GradientAlgorithm and the trainee is Differentiable, the trainee
+// calculates the gradient for the algorithm, otherwise the gradient is calculated using central
+// differences, as with Gradient. If the trainee is a BatchTrainee, all of the data is calculated
+// in a single call.
 func Complete(t Trainee, d []Data, a Algorithm, dist distance.Function, stoppers ...Stopper) (iterations int, err error) {
-	for {
-		e := func() (e float64, err error) {
-			return evaluateTrainee(t, d, dist)
+	return complete(t, d, a, dist, evaluator(t, d, dist), stoppers)
+}
+
+func complete(t Trainee, d []Data, a Algorithm, dist distance.Function, e Evaluator, stoppers []Stopper) (iterations int, err error) {
+	next := a.Next
+	if ga, ok := a.(GradientAlgorithm); ok {
+		var differentiator Differentiator
+		if dt, ok := t.(Differentiable); ok {
+			differentiator = Differentiate(dt, d, dist)
+		} else {
+			differentiator = centralDifferences(t, e)
+		}
+		next = func(Evaluator) ([]float64, error) {
+			return ga.NextWithGradient(differentiator)
 		}
-		updatedMemory, err := a.Next(e)
+	}
+	for {
+		updatedMemory, err := next(e)
 		if err != nil {
 			return iterations, fmt.Errorf("training.Complete: error at iteration %v: %v", iterations, err)
 		}
-		t.SetMemory(updatedMemory)
+		if err = t.SetMemory(updatedMemory); err != nil {
+			return iterations, fmt.Errorf("training.Complete: error setting memory at iteration %v: %v", iterations, err)
+		}
 
 		iterations++
 		if shouldStop(iterations, a.BestError(), stoppers) {
@@ -30,6 +49,49 @@ func Complete(t Trainee, d []Data, a Algorithm, dist distance.Function, stoppers
 	return
 }
 
+// centralDifferences returns a Differentiator which calculates the gradient of the error
+// calculated by ev by changing the trainee's memory.
+func centralDifferences(t Trainee, ev Evaluator) Differentiator {
+	return func() (e float64, g []float64, err error) {
+		if e, err = ev(); err != nil {
+			return
+		}
+		g, err = gradient(t, ev)
+		return
+	}
+}
+
+// evaluator returns an Evaluator for the trainee. A BatchTrainee calculates all of the data at
+// once, into outputs which are allocated once and reused by each evaluation.
+func evaluator(t Trainee, d []Data, dist distance.Function) Evaluator {
+	bt, ok := t.(BatchTrainee)
+	if !ok {
+		return func() (e float64, err error) {
+			return evaluateTrainee(t, d, dist)
+		}
+	}
+	inputs := make([][]float64, len(d))
+	outputs := make([][]float64, len(d))
+	for i, td := range d {
+		inputs[i] = td.Input
+		outputs[i] = make([]float64, len(td.Expected))
+	}
+	return func() (e float64, err error) {
+		if err = bt.CalculateBatch(inputs, outputs); err != nil {
+			return e, fmt.Errorf("error training data: %v", err)
+		}
+		for i, td := range d {
+			de, err := dist(outputs[i], td.Expected)
+			if err != nil {
+				return e, fmt.Errorf("error calculating distance: %v", err)
+			}
+			e += de
+		}
+		e = e / float64(len(d))
+		return
+	}
+}
+
 func evaluateTrainee(t Trainee, d []Data, dist distance.Function) (e float64, err error) {
 	for _, td := range d {
 		actual, err := t.Calculate(td.Input)
@@ -2,7 +2,10 @@ package training
 
 import (
 	"context"
+	"errors"
 	"testing"
+
+	"github.com/a-h/ml/distance"
 )
 
 func TestCompleteUsesAllTrainingData(tt *testing.T) {
@@ -50,12 +53,78 @@ func TestCompleteUsesAllTrainingData(tt *testing.T) {
 	}
 }
 
+func TestCompleteReturnsSetMemoryErrors(tt *testing.T) {
+	t := &traineeMock{setMemoryErr: errors.New("expected error")}
+	d := []Data{
+		{
+			Input:    []float64{0},
+			Expected: []float64{0},
+		},
+	}
+	a := &algorithmMock{}
+	dist := func(p []float64, q []float64) (d float64, err error) {
+		return 0.0, nil
+	}
+
+	iterations, err := Complete(t, d, a, dist, StopAfterXIterations(10))
+	if err == nil {
+		tt.Errorf("expected an error, got nil")
+	}
+	if iterations != 0 {
+		tt.Errorf("expected training to stop at the first iteration, but it stopped at %d", iterations)
+	}
+}
+
+func TestCompleteUsesCalculateBatch(tt *testing.T) {
+	t := &batchTraineeMock{}
+	d := []Data{
+		{
+			Input:    []float64{0},
+			Expected: []float64{0},
+		},
+		{
+			Input:    []float64{1},
+			Expected: []float64{2},
+		},
+	}
+	a := &algorithmMock{}
+
+	_, err := Complete(t, d, a, distance.SumOfSquares, StopAfterXIterations(3))
+	if err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+	if t.calculateCalled != 0 {
+		tt.Errorf("expected Calculate not to be called, but it was called %d times", t.calculateCalled)
+	}
+	if t.calculateBatchCalled != 3 {
+		tt.Errorf("expected CalculateBatch to be called once per iteration, but it was called %d times", t.calculateBatchCalled)
+	}
+	// The mock returns the input, so the error is (0 + 1) / 2.
+	if a.BestError() != 0.5 {
+		tt.Errorf("expected an error of 0.5, got %v", a.BestError())
+	}
+}
+
+type batchTraineeMock struct {
+	traineeMock
+	calculateBatchCalled int
+}
+
+func (tm *batchTraineeMock) CalculateBatch(inputs, outputs [][]float64) error {
+	tm.calculateBatchCalled++
+	for i, input := range inputs {
+		copy(outputs[i], input)
+	}
+	return nil
+}
+
 type traineeMock struct {
 	calculateCalled     int
 	getMemorySizeCalled int
 	getMemoryCalled     int
 	setMemoryCalled     int
 	memory              []float64
+	setMemoryErr        error
 }
 
 func (tm *traineeMock) Calculate(input []float64) (output []float64, err error) {
@@ -70,9 +139,13 @@ func (tm *traineeMock) GetMemory() []float64 {
 	tm.getMemoryCalled++
 	return tm.memory
 }
-func (tm *traineeMock) SetMemory(m []float64) {
+func (tm *traineeMock) SetMemory(m []float64) error {
 	tm.setMemoryCalled++
+	if tm.setMemoryErr != nil {
+		return tm.setMemoryErr
+	}
 	tm.memory = m
+	return nil
 }
 
 type algorithmMock struct {
@@ -92,7 +92,10 @@ func main() {
 		// Move some more.
 		memory, _ := t.Next(evaluator)
 		fmt.Println(memory)
-		trainee.SetMemory(memory)
+		if err := trainee.SetMemory(memory); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
 	}
 
 	gif.EncodeAll(file, &anim)
@@ -117,7 +120,11 @@ func (ft *FuncTrainee) GetMemory() []float64 {
 	return []float64{ft.X, ft.Y}
 }
 
-func (ft *FuncTrainee) SetMemory(m []float64) {
+func (ft *FuncTrainee) SetMemory(m []float64) error {
+	if len(m) != 2 {
+		return fmt.Errorf("expected 2 values, got %d", len(m))
+	}
 	ft.X = m[0]
 	ft.Y = m[1]
+	return nil
 }
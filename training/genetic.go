@@ -0,0 +1,285 @@
+package training
+
+import (
+	"math"
+	"sort"
+
+	"github.com/a-h/ml/random"
+)
+
+// A Selector chooses the index of a parent from a population, given the error of each member.
+// Lower errors are better.
+type Selector func(errors []float64) int
+
+// TournamentSelection chooses size members of the population at random, and selects the one with
+// the lowest error.
+func TournamentSelection(size int) Selector {
+	return func(errors []float64) int {
+		best := random.Intn(len(errors))
+		for i := 1; i < size; i++ {
+			if c := random.Intn(len(errors)); errors[c] < errors[best] {
+				best = c
+			}
+		}
+		return best
+	}
+}
+
+// RouletteSelection selects members of the population with a probability proportional to
+// 1 / (1 + e - min), where min is the lowest error in the population.
+func RouletteSelection() Selector {
+	return func(errors []float64) int {
+		min := math.Inf(1)
+		for _, e := range errors {
+			min = math.Min(min, e)
+		}
+		weights := make([]float64, len(errors))
+		var total float64
+		for i, e := range errors {
+			weights[i] = 1 / (1 + e - min)
+			total += weights[i]
+		}
+		return spin(weights, total)
+	}
+}
+
+// RankSelection sorts the population by error, and selects members with a probability
+// proportional to their rank, so that the best member is len(errors) times more likely to be
+// selected than the worst.
+func RankSelection() Selector {
+	return func(errors []float64) int {
+		order := rank(errors)
+		weights := make([]float64, len(errors))
+		for r, i := range order {
+			weights[i] = float64(len(errors) - r)
+		}
+		n := float64(len(errors))
+		return spin(weights, n*(n+1)/2)
+	}
+}
+
+// spin chooses an index with a probability proportional to its weight.
+func spin(weights []float64, total float64) int {
+	v := random.Float64(0, total)
+	for i, w := range weights {
+		if v < w {
+			return i
+		}
+		v -= w
+	}
+	return len(weights) - 1
+}
+
+// rank returns the indices of errors, ordered from the lowest error to the highest.
+func rank(errors []float64) []int {
+	order := make([]int, len(errors))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return errors[order[i]] < errors[order[j]]
+	})
+	return order
+}
+
+// A Crossover combines two parents to create a child.
+type Crossover func(a, b []float64) (child []float64)
+
+// UniformCrossover takes each value from either parent with equal probability.
+func UniformCrossover() Crossover {
+	return func(a, b []float64) []float64 {
+		child := make([]float64, len(a))
+		for i := range child {
+			if random.Intn(2) == 0 {
+				child[i] = a[i]
+			} else {
+				child[i] = b[i]
+			}
+		}
+		return child
+	}
+}
+
+// SinglePointCrossover takes the values before a random point from the first parent, and the
+// remaining values from the second.
+func SinglePointCrossover() Crossover {
+	return func(a, b []float64) []float64 {
+		child := make([]float64, len(a))
+		point := random.Intn(len(a) + 1)
+		copy(child, a[:point])
+		copy(child[point:], b[point:])
+		return child
+	}
+}
+
+// BlendCrossover (BLX-α) chooses each value at random from the range between the parents' values,
+// extended by alpha times the distance between them in each direction.
+func BlendCrossover(alpha float64) Crossover {
+	return func(a, b []float64) []float64 {
+		child := make([]float64, len(a))
+		for i := range child {
+			lo, hi := math.Min(a[i], b[i]), math.Max(a[i], b[i])
+			d := (hi - lo) * alpha
+			child[i] = random.Float64(lo-d, hi+d)
+		}
+		return child
+	}
+}
+
+// NewGeneticAlgorithm creates a genetic algorithm with a population of the given size. The
+// memory is the first member of the population, the others are chosen at random between Min
+// and Max when training starts.
+func NewGeneticAlgorithm(memory []float64, populationSize int) *GeneticAlgorithm {
+	min, max := -10.0, 10.0
+	return &GeneticAlgorithm{
+		Min:            min,
+		Max:            max,
+		PopulationSize: populationSize,
+		Selection:      TournamentSelection(3),
+		Crossover:      UniformCrossover(),
+		CrossoverRate:  0.9,
+		MutationRate:   0.1,
+		MutationScale:  0.1,
+		Elites:         1,
+		current:        memory,
+		e:              math.MaxFloat64,
+	}
+}
+
+// GeneticAlgorithm is a training algorithm which evolves a population of memory values. Each
+// generation, the Elites with the lowest error survive unchanged, and the rest of the population
+// is replaced by children, bred from parents chosen by the Selection.
+//
+// Each call to Next evaluates a single member of the population, so a generation takes
+// PopulationSize calls, less the number of Elites.
+type GeneticAlgorithm struct {
+	current []float64
+	// best memory and error recorded during training.
+	best []float64
+	e    float64
+	// Min and Max values for memory values.
+	Min, Max float64
+	// PopulationSize is the number of members of each generation.
+	PopulationSize int
+	// Selection chooses parents.
+	Selection Selector
+	// Crossover combines parents to create a child.
+	Crossover Crossover
+	// CrossoverRate is the probability that a child is bred from two parents, rather than copied
+	// from one.
+	CrossoverRate float64
+	// MutationRate is the probability that each value of a child is mutated.
+	MutationRate float64
+	// MutationScale is the standard deviation of the Gaussian noise added to mutated values, as a
+	// fraction of Max - Min.
+	MutationScale float64
+	// Elites is the number of members with the lowest error which survive to the next generation.
+	Elites int
+	// Generation is the number of the current generation, starting at zero.
+	Generation int
+
+	population [][]float64
+	errors     []float64
+	index      int
+}
+
+// Next records the error of the previous member of the population, and returns the next member
+// to evaluate.
+func (ga *GeneticAlgorithm) Next(ev Evaluator) ([]float64, error) {
+	e, err := ev()
+	if err != nil {
+		return ga.current, err
+	}
+	if ga.population == nil {
+		ga.initialise()
+	}
+	ga.record(e)
+	if ga.index == len(ga.population) {
+		ga.breed()
+	}
+	ga.current = ga.population[ga.index]
+	return ga.current, nil
+}
+
+func (ga *GeneticAlgorithm) initialise() {
+	size := ga.PopulationSize
+	if size < 2 {
+		size = 2
+	}
+	ga.population = make([][]float64, size)
+	ga.errors = make([]float64, size)
+	ga.population[0] = append([]float64(nil), ga.current...)
+	for i := 1; i < size; i++ {
+		ga.population[i] = random.Float64Vector(ga.Min, ga.Max, len(ga.current))
+	}
+}
+
+func (ga *GeneticAlgorithm) record(e float64) {
+	if math.IsNaN(e) {
+		e = math.Inf(1)
+	}
+	ga.errors[ga.index] = e
+	if e < ga.e {
+		ga.best = append(ga.best[:0], ga.population[ga.index]...)
+		ga.e = e
+	}
+	ga.index++
+}
+
+// breed replaces the population with the next generation.
+func (ga *GeneticAlgorithm) breed() {
+	order := rank(ga.errors)
+	elites := ga.Elites
+	if elites > len(ga.population)-1 {
+		elites = len(ga.population) - 1
+	}
+	if elites < 0 {
+		elites = 0
+	}
+	population := make([][]float64, len(ga.population))
+	errors := make([]float64, len(ga.errors))
+	for i := 0; i < elites; i++ {
+		population[i] = ga.population[order[i]]
+		errors[i] = ga.errors[order[i]]
+	}
+	scale := ga.MutationScale * (ga.Max - ga.Min)
+	for i := elites; i < len(population); i++ {
+		a := ga.population[ga.Selection(ga.errors)]
+		var child []float64
+		if random.Float64(0, 1) < ga.CrossoverRate {
+			child = ga.Crossover(a, ga.population[ga.Selection(ga.errors)])
+		} else {
+			child = append([]float64(nil), a...)
+		}
+		for j := range child {
+			if random.Float64(0, 1) < ga.MutationRate {
+				child[j] += random.Normal(0, scale)
+			}
+		}
+		clamp(child, ga.Min, ga.Max)
+		population[i] = child
+	}
+	ga.population = population
+	ga.errors = errors
+	ga.index = elites
+	ga.Generation++
+}
+
+// clamp the values of v between min and max.
+func clamp(v []float64, min, max float64) {
+	for i := range v {
+		v[i] = math.Max(min, math.Min(max, v[i]))
+	}
+}
+
+// BestError returns the best (lowest) error discovered by training.
+// If no training has happened, it will math.MaxFloat64.
+func (ga *GeneticAlgorithm) BestError() (e float64) {
+	return ga.e
+}
+
+// BestMemory returns the best set of parameters discovered by the algorithm during training.
+// If no training has happened, it will return nil.
+func (ga *GeneticAlgorithm) BestMemory() (memory []float64) {
+	return ga.best
+}
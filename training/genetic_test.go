@@ -0,0 +1,147 @@
+package training
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+)
+
+// lineData is y = 3x - 2.
+var lineData = []Data{
+	{Input: []float64{-2}, Expected: []float64{-8}},
+	{Input: []float64{-1}, Expected: []float64{-5}},
+	{Input: []float64{0}, Expected: []float64{-2}},
+	{Input: []float64{1}, Expected: []float64{1}},
+	{Input: []float64{2}, Expected: []float64{4}},
+}
+
+func TestGeneticAlgorithm(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection Selector
+		crossover Crossover
+	}{
+		{
+			name:      "tournament selection with uniform crossover",
+			selection: TournamentSelection(3),
+			crossover: UniformCrossover(),
+		},
+		{
+			name:      "roulette selection with single point crossover",
+			selection: RouletteSelection(),
+			crossover: SinglePointCrossover(),
+		},
+		{
+			name:      "rank selection with blend crossover",
+			selection: RankSelection(),
+			crossover: BlendCrossover(0.5),
+		},
+	}
+
+	for _, test := range tests {
+		trainee := &lineTrainee{memory: []float64{0, 0}}
+		ga := NewGeneticAlgorithm(trainee.GetMemory(), 50)
+		ga.Selection = test.selection
+		ga.Crossover = test.crossover
+		ga.Elites = 2
+		_, err := Complete(trainee, lineData, ga, distance.SumOfSquares, StopAfterXIterations(10000))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if ga.BestError() > 0.1 {
+			t.Errorf("%s: expected an error below 0.1, got %v with memory %v", test.name, ga.BestError(), ga.BestMemory())
+		}
+		if ga.Generation < 200 {
+			t.Errorf("%s: expected at least 200 generations, got %d", test.name, ga.Generation)
+		}
+		for _, v := range ga.population {
+			for _, vv := range v {
+				if vv < ga.Min || vv > ga.Max {
+					t.Fatalf("%s: expected the population to be between Min and Max, got %v", test.name, v)
+				}
+			}
+		}
+	}
+}
+
+func TestGeneticAlgorithmKeepsElites(t *testing.T) {
+	ga := NewGeneticAlgorithm([]float64{0, 0}, 10)
+	ga.Elites = 3
+	memory := ga.current
+	ev := func() (float64, error) {
+		return memory[0]*memory[0] + memory[1]*memory[1], nil
+	}
+	for i := 0; i < 10; i++ {
+		var err error
+		if memory, err = ga.Next(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if ga.Generation != 1 || ga.index != 3 {
+		t.Fatalf("expected to be evaluating the first child of generation 1, got generation %d, index %d", ga.Generation, ga.index)
+	}
+	// The starting memory has the lowest possible error.
+	if ga.population[0][0] != 0 || ga.population[0][1] != 0 || ga.errors[0] != 0 {
+		t.Errorf("expected the best member to survive, got %v with error %v", ga.population[0], ga.errors[0])
+	}
+	if ga.BestError() != 0 {
+		t.Errorf("expected a best error of 0, got %v", ga.BestError())
+	}
+}
+
+func TestSelection(t *testing.T) {
+	errors := []float64{5, 1, 3, 0.5, 10}
+	tests := []struct {
+		name     string
+		selector Selector
+	}{
+		{name: "tournament", selector: TournamentSelection(3)},
+		{name: "roulette", selector: RouletteSelection()},
+		{name: "rank", selector: RankSelection()},
+	}
+
+	for _, test := range tests {
+		counts := make([]int, len(errors))
+		for i := 0; i < 10000; i++ {
+			counts[test.selector(errors)]++
+		}
+		// The lowest error should be chosen most often, and the highest least often.
+		for i, c := range counts {
+			if i != 3 && c >= counts[3] {
+				t.Errorf("%s: expected index 3 to be selected most often, got %v", test.name, counts)
+				break
+			}
+			if i != 4 && c <= counts[4] {
+				t.Errorf("%s: expected index 4 to be selected least often, got %v", test.name, counts)
+				break
+			}
+		}
+	}
+}
+
+func TestCrossover(t *testing.T) {
+	a := []float64{1, 2, 3, 4, 5}
+	b := []float64{-1, -2, -3, -4, -5}
+	for i := 0; i < 100; i++ {
+		uniform := UniformCrossover()(a, b)
+		for j, v := range uniform {
+			if v != a[j] && v != b[j] {
+				t.Fatalf("uniform: expected each value to come from a parent, got %v", uniform)
+			}
+		}
+		single := SinglePointCrossover()(a, b)
+		for j := 1; j < len(single); j++ {
+			if single[j-1] < 0 && single[j] > 0 {
+				t.Fatalf("single point: expected values from a, then b, got %v", single)
+			}
+		}
+		blend := BlendCrossover(0.5)(a, b)
+		for j, v := range blend {
+			if math.Abs(v) > math.Abs(a[j])*2 {
+				t.Fatalf("blend: expected values within the extended range of the parents, got %v", blend)
+			}
+		}
+	}
+}
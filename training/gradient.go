@@ -0,0 +1,40 @@
+package training
+
+import (
+	"fmt"
+
+	"github.com/a-h/ml/calculus"
+	"github.com/a-h/ml/distance"
+)
+
+// Gradient returns the partial derivatives of the trainee's error against each value of its
+// memory, using central differences. The trainee's memory is restored before returning.
+func Gradient(t Trainee, d []Data, dist distance.Function) (g []float64, err error) {
+	return gradient(t, evaluator(t, d, dist))
+}
+
+// gradient calculates the gradient of the trainee's error, calculated by ev, and restores the
+// trainee's memory before returning.
+func gradient(t Trainee, ev Evaluator) (g []float64, err error) {
+	memory := append([]float64(nil), t.GetMemory()...)
+	defer func() {
+		if rerr := t.SetMemory(memory); rerr != nil && err == nil {
+			g, err = nil, fmt.Errorf("training.Gradient: unable to restore memory: %v", rerr)
+		}
+	}()
+	g = calculus.Gradient(memory, func(m []float64) float64 {
+		if err != nil {
+			return 0
+		}
+		if err = t.SetMemory(m); err != nil {
+			return 0
+		}
+		var e float64
+		e, err = ev()
+		return e
+	})
+	if err != nil {
+		return nil, fmt.Errorf("training.Gradient: %v", err)
+	}
+	return
+}
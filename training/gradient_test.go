@@ -0,0 +1,70 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+)
+
+func TestGradient(t *testing.T) {
+	// y = m[0] * x + m[1]
+	trainee := &lineTrainee{memory: []float64{1, 0}}
+	d := []Data{
+		{Input: []float64{1}, Expected: []float64{3}},
+		{Input: []float64{2}, Expected: []float64{5}},
+	}
+	g, err := Gradient(trainee, d, distance.SumOfSquares)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// error = ((m0 + m1 - 3)² + (2m0 + m1 - 5)²) / 2
+	// d/dm0 = (m0 + m1 - 3) + 2(2m0 + m1 - 5) = -2 - 6 = -8
+	// d/dm1 = (m0 + m1 - 3) + (2m0 + m1 - 5) = -2 - 3 = -5
+	expected := []float64{-8, -5}
+	for i := range expected {
+		if math.Abs(g[i]-expected[i]) > 1e-6 {
+			t.Errorf("expected %v, got %v", expected, g)
+			break
+		}
+	}
+	if trainee.memory[0] != 1 || trainee.memory[1] != 0 {
+		t.Errorf("expected the memory to be restored, but got %v", trainee.memory)
+	}
+}
+
+func TestGradientReturnsErrors(t *testing.T) {
+	trainee := &lineTrainee{memory: []float64{1, 0}}
+	d := []Data{
+		{Input: []float64{1}, Expected: []float64{3}},
+	}
+	dist := func(p, q []float64) (float64, error) {
+		return 0, errors.New("expected error")
+	}
+	if _, err := Gradient(trainee, d, dist); err == nil {
+		t.Errorf("expected an error, but didn't get one")
+	}
+}
+
+type lineTrainee struct {
+	memory []float64
+}
+
+func (lt *lineTrainee) Calculate(input []float64) (output []float64, err error) {
+	return []float64{lt.memory[0]*input[0] + lt.memory[1]}, nil
+}
+func (lt *lineTrainee) GetMemorySize() int {
+	return len(lt.memory)
+}
+func (lt *lineTrainee) GetMemory() []float64 {
+	return lt.memory
+}
+func (lt *lineTrainee) SetMemory(m []float64) error {
+	if len(m) != len(lt.memory) {
+		return fmt.Errorf("expected %d values, got %d", len(lt.memory), len(m))
+	}
+	lt.memory = m
+	return nil
+}
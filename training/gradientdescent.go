@@ -0,0 +1,165 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/a-h/ml/calculus"
+	"github.com/a-h/ml/distance"
+)
+
+// Differentiable is a Trainee which can calculate the gradient of its memory. Examples include
+// rbf.Network and rbf.Node.
+type Differentiable interface {
+	Trainee
+	// Backpropagate returns the gradient of the error with respect to each value of the memory,
+	// in the same order as GetMemory, given the input and the gradient of the error with respect
+	// to each output.
+	Backpropagate(input, outputGradient []float64) (memoryGradient []float64, err error)
+}
+
+// A Differentiator calculates the error of the trainee, and the gradient of the error with
+// respect to the trainee's memory.
+type Differentiator func() (e float64, g []float64, err error)
+
+// GradientAlgorithm is an Algorithm which can use the gradient of the error to train.
+type GradientAlgorithm interface {
+	Algorithm
+	NextWithGradient(d Differentiator) (updatedMemory []float64, err error)
+}
+
+// Differentiate returns a Differentiator which calculates the gradient of the error of the
+// trainee over the data using the trainee's Backpropagate method. The gradient of the distance
+// function is calculated using central differences.
+func Differentiate(t Differentiable, d []Data, dist distance.Function) Differentiator {
+	return func() (e float64, g []float64, err error) {
+		g = make([]float64, t.GetMemorySize())
+		for _, td := range d {
+			var actual []float64
+			actual, err = t.Calculate(td.Input)
+			if err != nil {
+				return e, g, fmt.Errorf("error training data: %v", err)
+			}
+			var de float64
+			de, err = dist(actual, td.Expected)
+			if err != nil {
+				return e, g, fmt.Errorf("error calculating distance: %v", err)
+			}
+			e += de
+			outputGradient := calculus.Gradient(actual, func(p []float64) float64 {
+				pe, _ := dist(p, td.Expected)
+				return pe
+			})
+			var mg []float64
+			mg, err = t.Backpropagate(td.Input, outputGradient)
+			if err != nil {
+				return e, g, fmt.Errorf("error calculating gradient: %v", err)
+			}
+			if len(mg) != len(g) {
+				return e, g, fmt.Errorf("error calculating gradient: expected %d values, got %d", len(g), len(mg))
+			}
+			for i, v := range mg {
+				g[i] += v
+			}
+		}
+		n := float64(len(d))
+		e /= n
+		for i := range g {
+			g[i] /= n
+		}
+		return
+	}
+}
+
+// NewSGD creates gradient descent training using momentum. A momentum of zero is plain
+// gradient descent.
+func NewSGD(memory []float64, learningRate, momentum float64) *GradientDescent {
+	return NewGradientDescent(memory, &Momentum{LearningRate: learningRate, Momentum: momentum})
+}
+
+// NewNesterov creates gradient descent training using Nesterov accelerated momentum.
+func NewNesterov(memory []float64, learningRate, momentum float64) *GradientDescent {
+	return NewGradientDescent(memory, &Nesterov{LearningRate: learningRate, Momentum: momentum})
+}
+
+// NewAdagrad creates gradient descent training using Adagrad.
+func NewAdagrad(memory []float64, learningRate float64) *GradientDescent {
+	return NewGradientDescent(memory, &Adagrad{LearningRate: learningRate, Epsilon: 1e-8})
+}
+
+// NewRMSProp creates gradient descent training using RMSProp.
+func NewRMSProp(memory []float64, learningRate, decay float64) *GradientDescent {
+	return NewGradientDescent(memory, &RMSProp{LearningRate: learningRate, Decay: decay, Epsilon: 1e-8})
+}
+
+// NewAdam creates gradient descent training using Adam, with the recommended decay rates of 0.9
+// and 0.999.
+func NewAdam(memory []float64, learningRate float64) *GradientDescent {
+	return NewGradientDescent(memory, &Adam{LearningRate: learningRate, Beta1: 0.9, Beta2: 0.999, Epsilon: 1e-8})
+}
+
+// NewGradientDescent creates gradient descent training which uses the optimiser to update the memory.
+func NewGradientDescent(memory []float64, o Optimiser) *GradientDescent {
+	return &GradientDescent{
+		Optimiser: o,
+		current:   memory,
+		e:         math.MaxFloat64,
+	}
+}
+
+// ErrGradientRequired is returned by GradientDescent.Next, since the Evaluator can't be used to
+// calculate the gradient.
+var ErrGradientRequired = errors.New("training: gradient descent requires a gradient, use NextWithGradient or training.Complete")
+
+// GradientDescent is a training algorithm which moves the memory against the gradient of the
+// error provided to NextWithGradient. training.Complete provides the gradient, calculated by the
+// trainee if it's Differentiable, or by central differences otherwise.
+type GradientDescent struct {
+	current []float64
+	// best memory and error recorded during training.
+	best []float64
+	e    float64
+	// Optimiser updates the memory from the gradient.
+	Optimiser Optimiser
+}
+
+// Next returns ErrGradientRequired, since the gradient can't be calculated from the Evaluator
+// alone.
+func (gd *GradientDescent) Next(ev Evaluator) ([]float64, error) {
+	return gd.current, ErrGradientRequired
+}
+
+// NextWithGradient uses the Differentiator to calculate the gradient of the error, and returns
+// the updated memory.
+func (gd *GradientDescent) NextWithGradient(d Differentiator) ([]float64, error) {
+	e, g, err := d()
+	if err != nil {
+		return gd.current, err
+	}
+	if len(g) != len(gd.current) {
+		return gd.current, fmt.Errorf("gradient has %d values, but the memory has %d", len(g), len(gd.current))
+	}
+	return gd.update(e, g), nil
+}
+
+func (gd *GradientDescent) update(e float64, g []float64) []float64 {
+	if e < gd.e {
+		gd.best = append(gd.best[:0], gd.current...)
+		gd.e = e
+	}
+	gd.Optimiser.Update(gd.current, g)
+	return gd.current
+}
+
+// BestError returns the best (lowest) error discovered by training.
+// If no training has happened, it will math.MaxFloat64.
+func (gd *GradientDescent) BestError() (e float64) {
+	return gd.e
+}
+
+// BestMemory returns the best set of parameters discovered by the algorithm during training.
+// If no training has happened, it will return nil.
+func (gd *GradientDescent) BestMemory() (memory []float64) {
+	return gd.best
+}
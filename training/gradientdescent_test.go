@@ -0,0 +1,188 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+)
+
+func TestGradientDescentOptimisers(t *testing.T) {
+	tests := []struct {
+		name       string
+		algorithm  func(memory []float64) *GradientDescent
+		iterations int
+	}{
+		{
+			name:       "sgd",
+			algorithm:  func(m []float64) *GradientDescent { return NewSGD(m, 0.1, 0) },
+			iterations: 200,
+		},
+		{
+			name:       "sgd with momentum",
+			algorithm:  func(m []float64) *GradientDescent { return NewSGD(m, 0.05, 0.5) },
+			iterations: 200,
+		},
+		{
+			name:       "nesterov",
+			algorithm:  func(m []float64) *GradientDescent { return NewNesterov(m, 0.05, 0.5) },
+			iterations: 200,
+		},
+		{
+			name:       "adagrad",
+			algorithm:  func(m []float64) *GradientDescent { return NewAdagrad(m, 1) },
+			iterations: 500,
+		},
+		{
+			name:       "rmsprop",
+			algorithm:  func(m []float64) *GradientDescent { return NewRMSProp(m, 0.01, 0.9) },
+			iterations: 1000,
+		},
+		{
+			name:       "adam",
+			algorithm:  func(m []float64) *GradientDescent { return NewAdam(m, 0.1) },
+			iterations: 1000,
+		},
+	}
+
+	for _, test := range tests {
+		// Find the minimum of (x - 3)² + 2(y + 1)².
+		memory := []float64{0, 0}
+		d := func() (e float64, g []float64, err error) {
+			e = (memory[0]-3)*(memory[0]-3) + 2*(memory[1]+1)*(memory[1]+1)
+			g = []float64{2 * (memory[0] - 3), 4 * (memory[1] + 1)}
+			return
+		}
+		gd := test.algorithm(memory)
+		for i := 0; i < test.iterations; i++ {
+			var err error
+			if memory, err = gd.NextWithGradient(d); err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+		}
+		if math.Abs(memory[0]-3) > 0.01 || math.Abs(memory[1]+1) > 0.01 {
+			t.Errorf("%s: expected the memory to be close to [3 -1], but got %v", test.name, memory)
+		}
+		if gd.BestError() > 0.001 {
+			t.Errorf("%s: expected the best error to be close to zero, but got %v", test.name, gd.BestError())
+		}
+	}
+}
+
+func TestGradientDescentWithGradient(t *testing.T) {
+	memory := []float64{0}
+	d := func() (e float64, g []float64, err error) {
+		return (memory[0] - 2) * (memory[0] - 2), []float64{2 * (memory[0] - 2)}, nil
+	}
+	gd := NewSGD(memory, 0.25, 0)
+	updated, err := gd.NextWithGradient(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated[0] != 1 {
+		t.Errorf("expected the memory to move halfway to the minimum, but got %v", updated)
+	}
+	if gd.BestError() != 4 || gd.BestMemory()[0] != 0 {
+		t.Errorf("expected the best error to be 4 at 0, but got %v at %v", gd.BestError(), gd.BestMemory())
+	}
+
+	_, err = gd.NextWithGradient(func() (float64, []float64, error) {
+		return 0, []float64{1, 2}, nil
+	})
+	if err == nil {
+		t.Errorf("expected an error for a gradient of the wrong length")
+	}
+}
+
+func TestGradientDescentReturnsErrorsFromTheDifferentiator(t *testing.T) {
+	gd := NewAdam([]float64{0, 1, 2}, 0.1)
+	d := func() (e float64, g []float64, err error) {
+		err = errors.New("expected error")
+		return
+	}
+	if _, err := gd.NextWithGradient(d); err == nil || err.Error() != "expected error" {
+		t.Errorf("unexpected error message while differentiating function: %v", err)
+	}
+}
+
+func TestGradientDescentRequiresAGradient(t *testing.T) {
+	gd := NewAdam([]float64{0, 1, 2}, 0.1)
+	ev := func() (e float64, err error) {
+		return 0, nil
+	}
+	if _, err := gd.Next(ev); err != ErrGradientRequired {
+		t.Errorf("expected %v, got %v", ErrGradientRequired, err)
+	}
+}
+
+func TestCompleteCalculatesGradientsOfTrainees(t *testing.T) {
+	// The trainee copies its memory, so changes to the algorithm's memory only reach it through
+	// SetMemory.
+	trainee := &scalarLineTrainee{}
+	gd := NewSGD(trainee.GetMemory(), 0.05, 0.5)
+	_, err := Complete(trainee, lineData, gd, distance.SumOfSquares, StopAfterXIterations(500))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(trainee.slope-3) > 1e-3 || math.Abs(trainee.intercept+2) > 1e-3 {
+		t.Errorf("expected the memory to be close to [3 -2], but got %v", trainee.GetMemory())
+	}
+}
+
+// scalarLineTrainee is y = slope * x + intercept.
+type scalarLineTrainee struct {
+	slope, intercept float64
+}
+
+func (lt *scalarLineTrainee) Calculate(input []float64) ([]float64, error) {
+	return []float64{lt.slope*input[0] + lt.intercept}, nil
+}
+
+func (lt *scalarLineTrainee) GetMemorySize() int {
+	return 2
+}
+
+func (lt *scalarLineTrainee) GetMemory() []float64 {
+	return []float64{lt.slope, lt.intercept}
+}
+
+func (lt *scalarLineTrainee) SetMemory(m []float64) error {
+	if len(m) != 2 {
+		return fmt.Errorf("expected 2 values, got %d", len(m))
+	}
+	lt.slope, lt.intercept = m[0], m[1]
+	return nil
+}
+
+func TestCompleteUsesDifferentiableTrainees(t *testing.T) {
+	// y = m[0] * x + m[1], with the answer y = 2x + 1.
+	trainee := &differentiableLineTrainee{lineTrainee: lineTrainee{memory: []float64{0, 0}}}
+	d := []Data{
+		{Input: []float64{0}, Expected: []float64{1}},
+		{Input: []float64{1}, Expected: []float64{3}},
+		{Input: []float64{2}, Expected: []float64{5}},
+	}
+	gd := NewSGD(trainee.GetMemory(), 0.1, 0.5)
+	_, err := Complete(trainee, d, gd, distance.SumOfSquares, StopAfterXIterations(500))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trainee.backpropagateCalled == 0 {
+		t.Errorf("expected the trainee's gradient to be used")
+	}
+	if math.Abs(trainee.memory[0]-2) > 1e-3 || math.Abs(trainee.memory[1]-1) > 1e-3 {
+		t.Errorf("expected the memory to be close to [2 1], but got %v", trainee.memory)
+	}
+}
+
+type differentiableLineTrainee struct {
+	lineTrainee
+	backpropagateCalled int
+}
+
+func (dt *differentiableLineTrainee) Backpropagate(input, outputGradient []float64) ([]float64, error) {
+	dt.backpropagateCalled++
+	return []float64{outputGradient[0] * input[0], outputGradient[0]}, nil
+}
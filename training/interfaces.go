@@ -6,7 +6,16 @@ type Trainee interface {
 	Calculate(input []float64) (output []float64, err error)
 	GetMemorySize() int
 	GetMemory() []float64
-	SetMemory(m []float64)
+	// SetMemory updates the trainee's memory, returning an error if m isn't the same length as
+	// the trainee's memory.
+	SetMemory(m []float64) error
+}
+
+// BatchTrainee is a Trainee which can calculate the outputs for many inputs at once, writing them
+// to preallocated outputs, e.g. rbf.Network. outputs contains a slice for each input.
+type BatchTrainee interface {
+	Trainee
+	CalculateBatch(inputs, outputs [][]float64) error
 }
 
 // An Evaluator executes a run of the training data against the trainee and determines the error.
@@ -0,0 +1,244 @@
+package training
+
+import (
+	"math"
+)
+
+// NewNelderMead creates Nelder-Mead training, with an initial simplex built around the memory.
+func NewNelderMead(memory []float64) *NelderMead {
+	min, max := -10.0, 10.0
+	return &NelderMead{
+		Min:         min,
+		Max:         max,
+		Step:        0.1,
+		Reflection:  1,
+		Expansion:   2,
+		Contraction: 0.5,
+		Shrinkage:   0.5,
+		Tolerance:   1e-9,
+		current:     memory,
+		e:           math.MaxFloat64,
+	}
+}
+
+// NelderMead is a training algorithm which moves a simplex of len(memory) + 1 vertices through the
+// memory space, by reflecting the vertex with the highest error through the centroid of the others,
+// expanding or contracting the simplex when the reflection is better or worse than expected, and
+// shrinking it towards the best vertex when no better point can be found.
+//
+// Each call to Next evaluates a single point. Once the simplex has collapsed, it's rebuilt around
+// the best vertex, which allows the search to continue if the simplex became degenerate before
+// reaching a minimum.
+type NelderMead struct {
+	current []float64
+	// best memory and error recorded during training.
+	best []float64
+	e    float64
+	// Min and Max values for memory values.
+	Min, Max float64
+	// Step is the distance from the initial point to each of the other vertices of a new simplex, as
+	// a fraction of Max - Min.
+	Step float64
+	// Reflection, Expansion, Contraction and Shrinkage are the coefficients of each operation,
+	// usually 1, 2, 0.5 and 0.5.
+	Reflection, Expansion, Contraction, Shrinkage float64
+	// Tolerance rebuilds the simplex once the distance between its vertices in every dimension is
+	// less than Tolerance, as a fraction of Max - Min.
+	Tolerance float64
+	// Restarts is the number of times the simplex has been rebuilt.
+	Restarts int
+
+	simplex [][]float64
+	errors  []float64
+	// step is the point of the algorithm that the current memory was chosen by.
+	step           nelderMeadStep
+	index          int
+	reflected      []float64
+	reflectedError float64
+}
+
+type nelderMeadStep int
+
+const (
+	nelderMeadBuild nelderMeadStep = iota
+	nelderMeadReflect
+	nelderMeadExpand
+	nelderMeadContractOutside
+	nelderMeadContractInside
+	nelderMeadShrink
+)
+
+// Next records the error of the previous point, and returns the next point to evaluate.
+func (nm *NelderMead) Next(ev Evaluator) ([]float64, error) {
+	e, err := ev()
+	if err != nil {
+		return nm.current, err
+	}
+	if math.IsNaN(e) {
+		e = math.Inf(1)
+	}
+	if e < nm.e {
+		nm.best = append(nm.best[:0], nm.current...)
+		nm.e = e
+	}
+	// There's nothing to search if the memory is empty.
+	if len(nm.current) == 0 {
+		return nm.current, nil
+	}
+	if nm.simplex == nil {
+		nm.build(nm.current)
+	}
+
+	worst := len(nm.simplex) - 1
+	switch nm.step {
+	case nelderMeadBuild, nelderMeadShrink:
+		nm.errors[nm.index] = e
+		nm.index++
+		if nm.index < len(nm.simplex) {
+			nm.current = nm.simplex[nm.index]
+			return nm.current, nil
+		}
+	case nelderMeadReflect:
+		switch {
+		case e < nm.errors[0]:
+			nm.reflected, nm.reflectedError = nm.current, e
+			nm.step = nelderMeadExpand
+			nm.current = nm.towards(nm.current, nm.Expansion)
+			return nm.current, nil
+		case e < nm.errors[worst-1]:
+			nm.replaceWorst(nm.current, e)
+		case e < nm.errors[worst]:
+			nm.reflected, nm.reflectedError = nm.current, e
+			nm.step = nelderMeadContractOutside
+			nm.current = nm.towards(nm.current, nm.Contraction)
+			return nm.current, nil
+		default:
+			nm.step = nelderMeadContractInside
+			nm.current = nm.towards(nm.simplex[worst], nm.Contraction)
+			return nm.current, nil
+		}
+	case nelderMeadExpand:
+		if e < nm.reflectedError {
+			nm.replaceWorst(nm.current, e)
+		} else {
+			nm.replaceWorst(nm.reflected, nm.reflectedError)
+		}
+	case nelderMeadContractOutside:
+		if e > nm.reflectedError {
+			return nm.shrink(), nil
+		}
+		nm.replaceWorst(nm.current, e)
+	case nelderMeadContractInside:
+		if e >= nm.errors[worst] {
+			return nm.shrink(), nil
+		}
+		nm.replaceWorst(nm.current, e)
+	}
+	return nm.reflect(), nil
+}
+
+// build a new simplex around x, which has already been evaluated.
+func (nm *NelderMead) build(x []float64) {
+	step := nm.Step * (nm.Max - nm.Min)
+	nm.simplex = make([][]float64, len(x)+1)
+	nm.errors = make([]float64, len(x)+1)
+	nm.simplex[0] = append([]float64(nil), x...)
+	for i := 1; i < len(nm.simplex); i++ {
+		v := append([]float64(nil), x...)
+		if v[i-1]+step <= nm.Max {
+			v[i-1] += step
+		} else {
+			v[i-1] -= step
+		}
+		clamp(v, nm.Min, nm.Max)
+		nm.simplex[i] = v
+	}
+	nm.step = nelderMeadBuild
+	nm.index = 0
+}
+
+// reflect sorts the simplex, and returns the reflection of the worst vertex through the centroid
+// of the others, unless the simplex has collapsed, in which case it's rebuilt.
+func (nm *NelderMead) reflect() []float64 {
+	order := rank(nm.errors)
+	simplex := make([][]float64, len(nm.simplex))
+	errors := make([]float64, len(nm.errors))
+	for i, o := range order {
+		simplex[i], errors[i] = nm.simplex[o], nm.errors[o]
+	}
+	nm.simplex, nm.errors = simplex, errors
+
+	if nm.collapsed() {
+		nm.Restarts++
+		nm.build(nm.simplex[0])
+		nm.errors[0] = errors[0]
+		nm.index = 1
+		nm.current = nm.simplex[1]
+		return nm.current
+	}
+	nm.step = nelderMeadReflect
+	nm.current = nm.towards(nm.simplex[len(nm.simplex)-1], -nm.Reflection)
+	return nm.current
+}
+
+// collapsed returns true if every vertex is within Tolerance of the best vertex.
+func (nm *NelderMead) collapsed() bool {
+	tolerance := nm.Tolerance * (nm.Max - nm.Min)
+	for _, v := range nm.simplex[1:] {
+		for j := range v {
+			if math.Abs(v[j]-nm.simplex[0][j]) > tolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// towards returns c + coefficient * (x - c), where c is the centroid of every vertex except the
+// worst.
+func (nm *NelderMead) towards(x []float64, coefficient float64) []float64 {
+	vertices := nm.simplex[:len(nm.simplex)-1]
+	v := make([]float64, len(x))
+	for j := range v {
+		var c float64
+		for _, vertex := range vertices {
+			c += vertex[j]
+		}
+		c /= float64(len(vertices))
+		v[j] = c + coefficient*(x[j]-c)
+	}
+	clamp(v, nm.Min, nm.Max)
+	return v
+}
+
+func (nm *NelderMead) replaceWorst(x []float64, e float64) {
+	nm.simplex[len(nm.simplex)-1] = x
+	nm.errors[len(nm.errors)-1] = e
+}
+
+// shrink moves every vertex towards the best vertex, and returns the first to evaluate.
+func (nm *NelderMead) shrink() []float64 {
+	for i := 1; i < len(nm.simplex); i++ {
+		v := make([]float64, len(nm.simplex[i]))
+		for j := range v {
+			v[j] = nm.simplex[0][j] + nm.Shrinkage*(nm.simplex[i][j]-nm.simplex[0][j])
+		}
+		nm.simplex[i] = v
+	}
+	nm.step = nelderMeadShrink
+	nm.index = 1
+	nm.current = nm.simplex[1]
+	return nm.current
+}
+
+// BestError returns the best (lowest) error discovered by training.
+// If no training has happened, it will math.MaxFloat64.
+func (nm *NelderMead) BestError() (e float64) {
+	return nm.e
+}
+
+// BestMemory returns the best set of parameters discovered by the algorithm during training.
+// If no training has happened, it will return nil.
+func (nm *NelderMead) BestMemory() (memory []float64) {
+	return nm.best
+}
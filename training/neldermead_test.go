@@ -0,0 +1,93 @@
+package training
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+)
+
+func TestNelderMead(t *testing.T) {
+	trainee := &lineTrainee{memory: []float64{0, 0}}
+	nm := NewNelderMead(trainee.GetMemory())
+	_, err := Complete(trainee, lineData, nm, distance.SumOfSquares, StopAfterXIterations(500))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nm.BestError() > 1e-6 {
+		t.Errorf("expected an error below 1e-6, got %v with memory %v", nm.BestError(), nm.BestMemory())
+	}
+}
+
+func TestNelderMeadRosenbrock(t *testing.T) {
+	nm := NewNelderMead([]float64{-1.2, 1})
+	nm.Min, nm.Max = -5, 5
+	memory := nm.current
+	ev := func() (float64, error) {
+		return rosenbrock(memory), nil
+	}
+	var err error
+	for i := 0; i < 2000; i++ {
+		if memory, err = nm.Next(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if nm.BestError() > 1e-8 {
+		t.Errorf("expected an error below 1e-8, got %v with memory %v", nm.BestError(), nm.BestMemory())
+	}
+	if nm.Restarts == 0 {
+		t.Errorf("expected the simplex to be rebuilt once it collapsed")
+	}
+}
+
+func TestNelderMeadShrink(t *testing.T) {
+	nm := NewNelderMead([]float64{0, 0})
+	// Every point other than the first is worse, so reflection and contraction fail.
+	memory := nm.current
+	ev := func() (float64, error) {
+		if memory[0] == 0 && memory[1] == 0 {
+			return 0, nil
+		}
+		return 1, nil
+	}
+	var err error
+	// Build the simplex, reflect, and contract.
+	for i := 0; i < 5; i++ {
+		if memory, err = nm.Next(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if nm.step != nelderMeadShrink {
+		t.Fatalf("expected the simplex to shrink, got step %v", nm.step)
+	}
+	expected := [][]float64{{0, 0}, {1, 0}, {0, 1}}
+	if !reflect.DeepEqual(nm.simplex, expected) {
+		t.Errorf("expected the simplex to shrink to %v, got %v", expected, nm.simplex)
+	}
+	if !reflect.DeepEqual(memory, expected[1]) {
+		t.Errorf("expected the first shrunk vertex to be evaluated next, got %v", memory)
+	}
+}
+
+func TestNelderMeadWithoutMemory(t *testing.T) {
+	a := NewNelderMead([]float64{})
+	ev := func() (float64, error) {
+		return 1, nil
+	}
+	for i := 0; i < 10; i++ {
+		memory, err := a.Next(ev)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(memory) != 0 {
+			t.Fatalf("expected empty memory, got %v", memory)
+		}
+	}
+	if a.BestError() != 1 {
+		t.Errorf("expected a best error of 1, got %v", a.BestError())
+	}
+}
+
+func rosenbrock(x []float64) float64 {
+	return (1-x[0])*(1-x[0]) + 100*(x[1]-x[0]*x[0])*(x[1]-x[0]*x[0])
+}
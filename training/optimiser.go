@@ -0,0 +1,119 @@
+package training
+
+import (
+	"math"
+)
+
+// An Optimiser updates memory in place, using the gradient of the error with respect to the memory.
+// Examples are training.Momentum and training.Adam.
+type Optimiser interface {
+	Update(memory, gradient []float64)
+}
+
+// Momentum is stochastic gradient descent with momentum. With a Momentum of zero, it's plain
+// gradient descent.
+type Momentum struct {
+	LearningRate float64
+	Momentum     float64
+	velocity     []float64
+}
+
+// Update moves the memory in the direction of the velocity, which is accumulated from the gradients.
+func (o *Momentum) Update(memory, gradient []float64) {
+	o.velocity = resize(o.velocity, len(memory))
+	for i, g := range gradient {
+		o.velocity[i] = o.Momentum*o.velocity[i] - o.LearningRate*g
+		memory[i] += o.velocity[i]
+	}
+}
+
+// Nesterov is stochastic gradient descent with Nesterov accelerated momentum, which looks ahead to
+// where the momentum will take the memory.
+type Nesterov struct {
+	LearningRate float64
+	Momentum     float64
+	velocity     []float64
+}
+
+// Update moves the memory using the Nesterov momentum update, reformulated so that the gradient
+// is calculated at the current memory rather than the look ahead position.
+func (o *Nesterov) Update(memory, gradient []float64) {
+	o.velocity = resize(o.velocity, len(memory))
+	for i, g := range gradient {
+		previous := o.velocity[i]
+		o.velocity[i] = o.Momentum*o.velocity[i] - o.LearningRate*g
+		memory[i] += -o.Momentum*previous + (1+o.Momentum)*o.velocity[i]
+	}
+}
+
+// Adagrad adapts the learning rate of each memory value, reducing it for values which have had
+// large gradients.
+type Adagrad struct {
+	LearningRate float64
+	// Epsilon avoids division by zero.
+	Epsilon      float64
+	sumOfSquares []float64
+}
+
+// Update moves the memory against the gradient, scaled by the sum of the squares of the previous
+// gradients.
+func (o *Adagrad) Update(memory, gradient []float64) {
+	o.sumOfSquares = resize(o.sumOfSquares, len(memory))
+	for i, g := range gradient {
+		o.sumOfSquares[i] += g * g
+		memory[i] -= o.LearningRate * g / (math.Sqrt(o.sumOfSquares[i]) + o.Epsilon)
+	}
+}
+
+// RMSProp adapts the learning rate of each memory value using a moving average of the squared
+// gradients, so unlike Adagrad, the learning rate doesn't continually decrease.
+type RMSProp struct {
+	LearningRate float64
+	// Decay rate of the moving average, e.g. 0.9.
+	Decay float64
+	// Epsilon avoids division by zero.
+	Epsilon float64
+	average []float64
+}
+
+// Update moves the memory against the gradient, scaled by the moving average of the squared gradients.
+func (o *RMSProp) Update(memory, gradient []float64) {
+	o.average = resize(o.average, len(memory))
+	for i, g := range gradient {
+		o.average[i] = o.Decay*o.average[i] + (1-o.Decay)*g*g
+		memory[i] -= o.LearningRate * g / (math.Sqrt(o.average[i]) + o.Epsilon)
+	}
+}
+
+// Adam combines momentum with RMSProp's adaptive learning rate.
+// See https://arxiv.org/abs/1412.6980
+type Adam struct {
+	LearningRate float64
+	// Decay rates of the moving averages of the gradient and squared gradient, e.g. 0.9 and 0.999.
+	Beta1, Beta2 float64
+	// Epsilon avoids division by zero.
+	Epsilon float64
+	m, v    []float64
+	t       int
+}
+
+// Update moves the memory using the bias corrected moving averages of the gradient and squared gradient.
+func (o *Adam) Update(memory, gradient []float64) {
+	o.m = resize(o.m, len(memory))
+	o.v = resize(o.v, len(memory))
+	o.t++
+	c1 := 1 - math.Pow(o.Beta1, float64(o.t))
+	c2 := 1 - math.Pow(o.Beta2, float64(o.t))
+	for i, g := range gradient {
+		o.m[i] = o.Beta1*o.m[i] + (1-o.Beta1)*g
+		o.v[i] = o.Beta2*o.v[i] + (1-o.Beta2)*g*g
+		memory[i] -= o.LearningRate * (o.m[i] / c1) / (math.Sqrt(o.v[i]/c2) + o.Epsilon)
+	}
+}
+
+func resize(s []float64, n int) []float64 {
+	if len(s) != n {
+		return make([]float64, n)
+	}
+	return s
+}
@@ -0,0 +1,136 @@
+package training
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/a-h/ml/distance"
+)
+
+// Cloner is implemented by trainees which aren't safe for concurrent use, so that each goroutine
+// of a parallel evaluation can use its own copy.
+type Cloner interface {
+	Clone() Trainee
+}
+
+// CompleteParallel is Complete, except that each evaluation of the data is split across workers
+// goroutines using NewParallelEvaluator. Gradients provided by a Differentiable trainee are still
+// calculated sequentially.
+func CompleteParallel(t Trainee, d []Data, a Algorithm, dist distance.Function, workers int, stoppers ...Stopper) (iterations int, err error) {
+	return complete(t, d, a, dist, NewParallelEvaluator(t, d, dist, workers), stoppers)
+}
+
+// NewParallelEvaluator returns an Evaluator which splits the data into workers contiguous shards,
+// and calculates each shard in its own goroutine. If workers is zero or less, GOMAXPROCS is used.
+//
+// If the trainee is a Cloner, each goroutine uses its own clone, which is given the trainee's
+// memory before each evaluation. Otherwise, the trainee's Calculate (or CalculateBatch) method
+// must be safe for concurrent use, as it is for rbf.Network.
+//
+// The error of each item of data is summed in order once all goroutines have completed, so the
+// result is the same as evaluating the data sequentially, whatever the number of workers.
+func NewParallelEvaluator(t Trainee, d []Data, dist distance.Function, workers int) Evaluator {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(d) {
+		workers = len(d)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	cloner, cloneable := t.(Cloner)
+	distances := make([]float64, len(d))
+	shards := make([]*shard, workers)
+	for i := range shards {
+		start, end := i*len(d)/workers, (i+1)*len(d)/workers
+		st := t
+		if cloneable {
+			st = cloner.Clone()
+		}
+		shards[i] = newShard(st, d[start:end], dist, distances[start:end])
+	}
+	return func() (e float64, err error) {
+		var memory []float64
+		if cloneable {
+			memory = t.GetMemory()
+		}
+		var wg sync.WaitGroup
+		for _, s := range shards {
+			wg.Add(1)
+			go func(s *shard) {
+				defer wg.Done()
+				if cloneable {
+					if s.err = s.t.SetMemory(memory); s.err != nil {
+						s.err = fmt.Errorf("error setting the memory of a clone: %v", s.err)
+						return
+					}
+				}
+				s.err = s.evaluate()
+			}(s)
+		}
+		wg.Wait()
+		// Return the error from the first shard, so that the same error is returned each time.
+		for _, s := range shards {
+			if s.err != nil {
+				return e, s.err
+			}
+		}
+		for _, de := range distances {
+			e += de
+		}
+		e = e / float64(len(d))
+		return
+	}
+}
+
+// shard is a contiguous section of the data, evaluated by a single goroutine.
+type shard struct {
+	t    Trainee
+	d    []Data
+	dist distance.Function
+	// distances receives the error of each item of data.
+	distances []float64
+	// inputs and outputs are used if t is a BatchTrainee.
+	inputs  [][]float64
+	outputs [][]float64
+	err     error
+}
+
+func newShard(t Trainee, d []Data, dist distance.Function, distances []float64) *shard {
+	s := &shard{t: t, d: d, dist: dist, distances: distances}
+	if _, ok := t.(BatchTrainee); ok {
+		s.inputs = make([][]float64, len(d))
+		s.outputs = make([][]float64, len(d))
+		for i, td := range d {
+			s.inputs[i] = td.Input
+			s.outputs[i] = make([]float64, len(td.Expected))
+		}
+	}
+	return s
+}
+
+func (s *shard) evaluate() (err error) {
+	if bt, ok := s.t.(BatchTrainee); ok {
+		if err = bt.CalculateBatch(s.inputs, s.outputs); err != nil {
+			return fmt.Errorf("error training data: %v", err)
+		}
+		for i, td := range s.d {
+			if s.distances[i], err = s.dist(s.outputs[i], td.Expected); err != nil {
+				return fmt.Errorf("error calculating distance: %v", err)
+			}
+		}
+		return
+	}
+	for i, td := range s.d {
+		actual, err := s.t.Calculate(td.Input)
+		if err != nil {
+			return fmt.Errorf("error training data: %v", err)
+		}
+		if s.distances[i], err = s.dist(actual, td.Expected); err != nil {
+			return fmt.Errorf("error calculating distance: %v", err)
+		}
+	}
+	return
+}
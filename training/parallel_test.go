@@ -0,0 +1,140 @@
+package training
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+	"github.com/a-h/ml/random"
+)
+
+func parallelTestData(count int) []Data {
+	d := make([]Data, count)
+	for i := range d {
+		x := random.Float64(-10, 10)
+		d[i] = Data{Input: []float64{x}, Expected: []float64{3*x + random.Float64(-1, 1)}}
+	}
+	return d
+}
+
+func TestParallelEvaluatorMatchesSequential(t *testing.T) {
+	d := parallelTestData(101)
+	trainee := &lineTrainee{memory: []float64{2.5, 0.5}}
+	expected, err := evaluateTrainee(trainee, d, distance.SumOfSquares)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, workers := range []int{0, 1, 2, 3, 7, 101, 200} {
+		actual, err := NewParallelEvaluator(trainee, d, distance.SumOfSquares, workers)()
+		if err != nil {
+			t.Errorf("%d workers: unexpected error: %v", workers, err)
+			continue
+		}
+		if actual != expected {
+			t.Errorf("%d workers: expected %v, got %v", workers, expected, actual)
+		}
+	}
+}
+
+func TestParallelEvaluatorUsesClones(t *testing.T) {
+	d := parallelTestData(20)
+	trainee := &cloningTrainee{lineTrainee: lineTrainee{memory: []float64{1, 2}}}
+	ev := NewParallelEvaluator(trainee, d, distance.SumOfSquares, 4)
+	if len(trainee.clones) != 4 {
+		t.Fatalf("expected 4 clones, got %d", len(trainee.clones))
+	}
+
+	// Change the memory after the evaluator has been created.
+	if err := trainee.SetMemory([]float64{3, 0}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := evaluateTrainee(trainee, d, distance.SumOfSquares)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := ev()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	for i, c := range trainee.clones {
+		if c.calculateCalled != 5 {
+			t.Errorf("clone %d: expected 5 calculations, got %d", i, c.calculateCalled)
+		}
+	}
+}
+
+func TestParallelEvaluatorReturnsErrors(t *testing.T) {
+	d := parallelTestData(20)
+	trainee := &lineTrainee{memory: []float64{1, 2}}
+	dist := func(p, q []float64) (float64, error) {
+		if q[0] == d[15].Expected[0] {
+			return 0, errors.New("expected error")
+		}
+		return distance.SumOfSquares(p, q)
+	}
+	if _, err := NewParallelEvaluator(trainee, d, dist, 4)(); err == nil {
+		t.Errorf("expected an error, got nil")
+	}
+}
+
+func TestCompleteParallelMatchesComplete(t *testing.T) {
+	d := parallelTestData(50)
+
+	sequential := &lineTrainee{memory: []float64{0, 0}}
+	sa := NewAdam(sequential.GetMemory(), 0.1)
+	if _, err := Complete(sequential, d, sa, distance.SumOfSquares, StopAfterXIterations(100)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parallel := &lineTrainee{memory: []float64{0, 0}}
+	pa := NewAdam(parallel.GetMemory(), 0.1)
+	if _, err := CompleteParallel(parallel, d, pa, distance.SumOfSquares, 4, StopAfterXIterations(100)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sa.BestError() != pa.BestError() {
+		t.Errorf("expected the same error as sequential training (%v), got %v", sa.BestError(), pa.BestError())
+	}
+}
+
+// cloningTrainee counts calculations, so it isn't safe for concurrent use.
+type cloningTrainee struct {
+	lineTrainee
+	calculateCalled int
+	clones          []*cloningTrainee
+}
+
+func (ct *cloningTrainee) Calculate(input []float64) ([]float64, error) {
+	ct.calculateCalled++
+	return ct.lineTrainee.Calculate(input)
+}
+
+func (ct *cloningTrainee) Clone() Trainee {
+	c := &cloningTrainee{lineTrainee: lineTrainee{memory: make([]float64, len(ct.memory))}}
+	ct.clones = append(ct.clones, c)
+	return c
+}
+
+func BenchmarkEvaluateSequential(b *testing.B) {
+	d := parallelTestData(10000)
+	trainee := &lineTrainee{memory: []float64{2.5, 0.5}}
+	ev := evaluator(trainee, d, distance.SumOfSquares)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ev()
+	}
+}
+
+func BenchmarkEvaluateParallel(b *testing.B) {
+	d := parallelTestData(10000)
+	trainee := &lineTrainee{memory: []float64{2.5, 0.5}}
+	ev := NewParallelEvaluator(trainee, d, distance.SumOfSquares, 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ev()
+	}
+}
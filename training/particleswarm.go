@@ -0,0 +1,189 @@
+package training
+
+import (
+	"math"
+
+	"github.com/a-h/ml/random"
+)
+
+// A Topology returns the indices of the particles whose best positions the particle is attracted
+// to, in a swarm of the given size.
+type Topology func(particle, size int) (neighbours []int)
+
+// GlobalTopology connects every particle to every other particle, so that each is attracted to
+// the best position found by the swarm.
+func GlobalTopology() Topology {
+	return func(particle, size int) []int {
+		neighbours := make([]int, size)
+		for i := range neighbours {
+			neighbours[i] = i
+		}
+		return neighbours
+	}
+}
+
+// RingTopology arranges the particles in a ring, connecting each particle to itself and to radius
+// particles on either side. Information spreads through the swarm more slowly than with
+// GlobalTopology, which makes it less likely to converge on a local minimum.
+func RingTopology(radius int) Topology {
+	return func(particle, size int) []int {
+		if 2*radius+1 >= size {
+			return GlobalTopology()(particle, size)
+		}
+		neighbours := make([]int, 0, 2*radius+1)
+		for i := -radius; i <= radius; i++ {
+			neighbours = append(neighbours, ((particle+i)%size+size)%size)
+		}
+		return neighbours
+	}
+}
+
+// NewParticleSwarm creates particle swarm training with the given number of particles. The memory
+// is the starting position of the first particle, the others start at random positions between
+// Min and Max when training starts.
+func NewParticleSwarm(memory []float64, particles int) *ParticleSwarm {
+	min, max := -10.0, 10.0
+	return &ParticleSwarm{
+		Min:         min,
+		Max:         max,
+		Particles:   particles,
+		Inertia:     0.7298,
+		Cognitive:   1.49618,
+		Social:      1.49618,
+		MaxVelocity: 0.2,
+		Topology:    GlobalTopology(),
+		current:     memory,
+		e:           math.MaxFloat64,
+	}
+}
+
+// ParticleSwarm is a training algorithm which moves a swarm of particles through the memory
+// space. Each particle is attracted to the best position it has found, and to the best position
+// found by its neighbours.
+//
+// Each call to Next evaluates a single particle, so the swarm moves every Particles calls.
+type ParticleSwarm struct {
+	current []float64
+	// best memory and error recorded during training.
+	best []float64
+	e    float64
+	// Min and Max values for memory values.
+	Min, Max float64
+	// Particles is the size of the swarm.
+	Particles int
+	// Inertia is the proportion of a particle's velocity kept at each move.
+	Inertia float64
+	// Cognitive is the attraction of a particle to its own best position.
+	Cognitive float64
+	// Social is the attraction of a particle to the best position of its neighbours.
+	Social float64
+	// MaxVelocity limits the speed of a particle in each dimension, as a fraction of Max - Min.
+	MaxVelocity float64
+	// Topology connects particles to their neighbours.
+	Topology Topology
+	// Iteration is the number of times the swarm has moved.
+	Iteration int
+
+	positions  [][]float64
+	velocities [][]float64
+	bests      [][]float64
+	bestErrors []float64
+	index      int
+}
+
+// Next records the error of the previous particle, and returns the position of the next particle
+// to evaluate.
+func (ps *ParticleSwarm) Next(ev Evaluator) ([]float64, error) {
+	e, err := ev()
+	if err != nil {
+		return ps.current, err
+	}
+	if ps.positions == nil {
+		ps.initialise()
+	}
+	ps.record(e)
+	if ps.index == len(ps.positions) {
+		ps.move()
+	}
+	ps.current = ps.positions[ps.index]
+	return ps.current, nil
+}
+
+func (ps *ParticleSwarm) initialise() {
+	size := ps.Particles
+	if size < 1 {
+		size = 1
+	}
+	vmax := ps.MaxVelocity * (ps.Max - ps.Min)
+	ps.positions = make([][]float64, size)
+	ps.velocities = make([][]float64, size)
+	ps.bests = make([][]float64, size)
+	ps.bestErrors = make([]float64, size)
+	for i := range ps.positions {
+		if i == 0 {
+			ps.positions[i] = append([]float64(nil), ps.current...)
+		} else {
+			ps.positions[i] = random.Float64Vector(ps.Min, ps.Max, len(ps.current))
+		}
+		ps.velocities[i] = random.Float64Vector(-vmax, vmax, len(ps.current))
+		ps.bestErrors[i] = math.Inf(1)
+	}
+}
+
+func (ps *ParticleSwarm) record(e float64) {
+	if math.IsNaN(e) {
+		e = math.Inf(1)
+	}
+	position := ps.positions[ps.index]
+	if e < ps.bestErrors[ps.index] || ps.bests[ps.index] == nil {
+		ps.bests[ps.index] = append(ps.bests[ps.index][:0], position...)
+		ps.bestErrors[ps.index] = e
+	}
+	if e < ps.e {
+		ps.best = append(ps.best[:0], position...)
+		ps.e = e
+	}
+	ps.index++
+}
+
+// move updates the velocity and position of every particle.
+func (ps *ParticleSwarm) move() {
+	vmax := ps.MaxVelocity * (ps.Max - ps.Min)
+	for i, position := range ps.positions {
+		neighbourhoodBest := ps.bests[i]
+		neighbourhoodError := ps.bestErrors[i]
+		for _, n := range ps.Topology(i, len(ps.positions)) {
+			if ps.bestErrors[n] < neighbourhoodError {
+				neighbourhoodBest = ps.bests[n]
+				neighbourhoodError = ps.bestErrors[n]
+			}
+		}
+		velocity := ps.velocities[i]
+		for j := range position {
+			velocity[j] = ps.Inertia*velocity[j] +
+				ps.Cognitive*random.Float64(0, 1)*(ps.bests[i][j]-position[j]) +
+				ps.Social*random.Float64(0, 1)*(neighbourhoodBest[j]-position[j])
+			velocity[j] = math.Max(-vmax, math.Min(vmax, velocity[j]))
+			position[j] += velocity[j]
+			// Stop at the boundary.
+			if position[j] < ps.Min || position[j] > ps.Max {
+				position[j] = math.Max(ps.Min, math.Min(ps.Max, position[j]))
+				velocity[j] = 0
+			}
+		}
+	}
+	ps.index = 0
+	ps.Iteration++
+}
+
+// BestError returns the best (lowest) error discovered by training.
+// If no training has happened, it will math.MaxFloat64.
+func (ps *ParticleSwarm) BestError() (e float64) {
+	return ps.e
+}
+
+// BestMemory returns the best set of parameters discovered by the algorithm during training.
+// If no training has happened, it will return nil.
+func (ps *ParticleSwarm) BestMemory() (memory []float64) {
+	return ps.best
+}
@@ -0,0 +1,92 @@
+package training
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/ml/distance"
+)
+
+func TestTopologies(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology Topology
+		particle int
+		size     int
+		expected []int
+	}{
+		{name: "global", topology: GlobalTopology(), particle: 2, size: 4, expected: []int{0, 1, 2, 3}},
+		{name: "ring", topology: RingTopology(1), particle: 2, size: 6, expected: []int{1, 2, 3}},
+		{name: "ring wraps at the start", topology: RingTopology(2), particle: 0, size: 6, expected: []int{4, 5, 0, 1, 2}},
+		{name: "ring wraps at the end", topology: RingTopology(1), particle: 5, size: 6, expected: []int{4, 5, 0}},
+		{name: "ring larger than the swarm", topology: RingTopology(2), particle: 1, size: 3, expected: []int{0, 1, 2}},
+	}
+
+	for _, test := range tests {
+		actual := test.topology(test.particle, test.size)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestParticleSwarm(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology Topology
+	}{
+		{name: "global", topology: GlobalTopology()},
+		{name: "ring", topology: RingTopology(1)},
+	}
+
+	for _, test := range tests {
+		trainee := &lineTrainee{memory: []float64{0, 0}}
+		ps := NewParticleSwarm(trainee.GetMemory(), 20)
+		ps.Topology = test.topology
+		_, err := Complete(trainee, lineData, ps, distance.SumOfSquares, StopAfterXIterations(4000))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if ps.BestError() > 1e-3 {
+			t.Errorf("%s: expected an error below 1e-3, got %v with memory %v", test.name, ps.BestError(), ps.BestMemory())
+		}
+		if ps.Iteration != 200 {
+			t.Errorf("%s: expected the swarm to move 200 times, got %d", test.name, ps.Iteration)
+		}
+	}
+}
+
+func TestParticleSwarmBounds(t *testing.T) {
+	ps := NewParticleSwarm([]float64{0, 0}, 10)
+	ps.Min, ps.Max = -1, 1
+	ps.MaxVelocity = 0.1
+	memory := ps.current
+	// The error falls towards +∞, so the particles are pushed against Max.
+	ev := func() (float64, error) {
+		return -memory[0] - memory[1], nil
+	}
+	for i := 0; i < 1000; i++ {
+		var err error
+		if memory, err = ps.Next(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, p := range ps.positions {
+			for _, v := range p {
+				if v < ps.Min || v > ps.Max {
+					t.Fatalf("expected positions between Min and Max, got %v", p)
+				}
+			}
+		}
+		for _, v := range ps.velocities {
+			for _, vv := range v {
+				if vv < -0.2 || vv > 0.2 {
+					t.Fatalf("expected velocities to be limited to 0.2, got %v", v)
+				}
+			}
+		}
+	}
+	if ps.BestError() != -2 {
+		t.Errorf("expected the best error to be at the boundary, got %v at %v", ps.BestError(), ps.BestMemory())
+	}
+}